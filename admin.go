@@ -0,0 +1,164 @@
+package outis
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// adminShutdownTimeout is the time given to the requests in progress when the admin server is closed
+const adminShutdownTimeout = 5 * time.Second
+
+// AdminMiddleware wraps the handler of the admin API, such as to authenticate the requests
+type AdminMiddleware func(http.Handler) http.Handler
+
+// AdminBasicAuth returns a middleware that requires the HTTP basic authentication with the user and password
+func AdminBasicAuth(user, password string) AdminMiddleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			reqUser, reqPassword, ok := req.BasicAuth()
+			if !ok || subtle.ConstantTimeCompare([]byte(reqUser), []byte(user)) != 1 ||
+				subtle.ConstantTimeCompare([]byte(reqPassword), []byte(password)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="outis"`)
+				writeAdminError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// AdminHandler returns the handler of the admin API, which allows listing the routines of the
+// watcher and triggering, pausing, resuming and cancelling the executions of a routine, or stopping it:
+//
+//	GET  /routines
+//	GET  /routines/{id}
+//	POST /routines/{id}/trigger   (optional body with the metadata of the execution)
+//	POST /routines/{id}/pause
+//	POST /routines/{id}/resume
+//	POST /routines/{id}/cancel
+//	POST /routines/{id}/stop
+//
+// The handler has no authentication, it must be wrapped by the application when mounted in its own server
+func (watch *Watch) AdminHandler() http.Handler {
+	return http.HandlerFunc(watch.serveAdmin)
+}
+
+func (watch *Watch) serveAdmin(w http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if parts[0] != "routines" || len(parts) > 3 {
+		writeAdminError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if len(parts) == 1 {
+		if req.Method != http.MethodGet {
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		writeAdminJSON(w, http.StatusOK, watch.Routines())
+		return
+	}
+
+	r, found := watch.registry.find(ID(parts[1]))
+	if !found {
+		writeAdminError(w, http.StatusNotFound, "routine not found")
+		return
+	}
+
+	if len(parts) == 2 {
+		if req.Method != http.MethodGet {
+			writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		writeAdminJSON(w, http.StatusOK, r.snapshot())
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		writeAdminError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id := ID(parts[1])
+	switch parts[2] {
+	case "trigger":
+		// O corpo da requisição, opcional, contém os metadados adicionados à execução
+		var metadata Metadata
+		if err := json.NewDecoder(req.Body).Decode(&metadata); err != nil && !errors.Is(err, io.EOF) {
+			writeAdminError(w, http.StatusBadRequest, "invalid metadata: "+err.Error())
+			return
+		}
+
+		if !r.requestTrigger(metadata) {
+			writeAdminError(w, http.StatusConflict, ErrTriggerPending.Error())
+			return
+		}
+		watch.log.Info("Execution triggered by the admin API", LogFields{"routine_id": id, "metadata": metadata})
+		writeAdminJSON(w, http.StatusAccepted, r.snapshot())
+	case "pause":
+		r.setPaused(true)
+		watch.log.Info("Routine paused by the admin API", LogFields{"routine_id": id})
+		writeAdminJSON(w, http.StatusOK, r.snapshot())
+	case "resume":
+		r.setPaused(false)
+		watch.log.Info("Routine resumed by the admin API", LogFields{"routine_id": id})
+		writeAdminJSON(w, http.StatusOK, r.snapshot())
+	case "cancel":
+		cancelled := r.cancel()
+		watch.log.Info("Executions cancelled by the admin API", LogFields{"routine_id": id, "cancelled": cancelled})
+		writeAdminJSON(w, http.StatusOK, map[string]int{"cancelled": cancelled})
+	case "stop":
+		r.stop()
+		watch.log.Info("Routine stopped by the admin API", LogFields{"routine_id": id})
+		writeAdminJSON(w, http.StatusAccepted, r.snapshot())
+	default:
+		writeAdminError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// adminServerHandler returns the handler of the admin API wrapped by the middlewares of the watcher
+func (watch *Watch) adminServerHandler() http.Handler {
+	handler := watch.AdminHandler()
+	for i := len(watch.adminMiddlewares) - 1; i >= 0; i-- {
+		handler = watch.adminMiddlewares[i](handler)
+	}
+
+	return handler
+}
+
+// listenAdmin serves the admin API on the address until the watcher is shut down
+func (watch *Watch) listenAdmin(addr string) {
+	server := &http.Server{Addr: addr, Handler: watch.adminServerHandler(), ReadHeaderTimeout: adminShutdownTimeout}
+
+	go func() {
+		<-watch.registry.stopped
+
+		ctx, cancel := context.WithTimeout(context.Background(), adminShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	watch.log.Info("Admin API listening on " + addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		watch.log.Error(err)
+	}
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeAdminError(w http.ResponseWriter, status int, msg string) {
+	writeAdminJSON(w, status, map[string]string{"error": msg})
+}
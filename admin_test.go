@@ -0,0 +1,100 @@
+package outis_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+	"github.com/Brisanet/outis/outistest"
+)
+
+func TestTriggerKeepsNextRun(t *testing.T) {
+	for name, policy := range map[string]outis.OverlapPolicy{
+		"skip":  outis.OverlapSkip(),
+		"delay": outis.OverlapDelay(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var (
+				clock = outistest.NewFakeClock(start)
+				watch = newClockWatcher(t, clock)
+
+				mu        sync.Mutex
+				scheduled []time.Time
+			)
+
+			watch.Go(
+				outis.WithID("routine"),
+				outis.WithName("routine"),
+				outis.WithInterval(10*time.Minute),
+				outis.WithOverlapPolicy(policy),
+				outis.WithScript(func(ctx outis.Context) error {
+					mu.Lock()
+					defer mu.Unlock()
+					scheduled = append(scheduled, ctx.ScheduledAt())
+					return nil
+				}),
+			)
+			executions := func() []time.Time {
+				mu.Lock()
+				defer mu.Unlock()
+				return append([]time.Time(nil), scheduled...)
+			}
+
+			clock.BlockUntil(1)
+			clock.Advance(4 * time.Minute)
+			require.NoError(t, watch.TriggerNow("routine", nil))
+
+			require.Eventually(t, func() bool { return len(executions()) == 1 }, 5*time.Second, time.Millisecond)
+			require.Eventually(t, func() bool {
+				snapshot, _ := watch.Routine("routine")
+				return snapshot.NextRun.Equal(start.Add(10 * time.Minute))
+			}, 5*time.Second, time.Millisecond, "the manual execution must not postpone the next run")
+
+			clock.BlockUntil(1)
+			clock.Advance(6 * time.Minute)
+			require.Eventually(t, func() bool { return len(executions()) == 2 }, 5*time.Second, time.Millisecond)
+			assert.Equal(t, []time.Time{start.Add(4 * time.Minute), start.Add(10 * time.Minute)}, executions())
+		})
+	}
+}
+
+func TestAdminBasicAuth(t *testing.T) {
+	var (
+		watch   = newClockWatcher(t, outistest.NewFakeClock(start))
+		handler = outis.AdminBasicAuth("admin", "secret")(watch.AdminHandler())
+	)
+
+	for _, test := range []struct {
+		name           string
+		user, password string
+		auth           bool
+		status         int
+	}{
+		{name: "without credentials", status: http.StatusUnauthorized},
+		{name: "wrong user", user: "other", password: "secret", auth: true, status: http.StatusUnauthorized},
+		{name: "wrong password", user: "admin", password: "other", auth: true, status: http.StatusUnauthorized},
+		{name: "valid credentials", user: "admin", password: "secret", auth: true, status: http.StatusOK},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/routines", nil)
+			if test.auth {
+				req.SetBasicAuth(test.user, test.password)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			if test.status == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="outis"`, rec.Header().Get("WWW-Authenticate"))
+				assert.JSONEq(t, `{"error":"unauthorized"}`, rec.Body.String())
+			}
+		})
+	}
+}
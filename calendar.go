@@ -0,0 +1,355 @@
+package outis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	calendarDateLayout   = "2006-01-02"
+	calendarYearlyLayout = "01-02"
+	icalDateLayout       = "20060102"
+	icalDateTimeLayout   = "20060102T150405"
+)
+
+// icalTextReplacer unescapes the text values of an iCalendar
+var icalTextReplacer = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`)
+
+// Calendar defines the dates in which the routines are not executed, such as holidays.
+// The calendar must not be changed after it is used by a routine
+type Calendar struct {
+	dates  map[string]string
+	yearly map[string]string
+	// periods são os eventos com horário, que marcam os dias que tocam no fuso da data consultada
+	periods []calendarPeriod
+}
+
+// calendarPeriod defines an event of the calendar with start and end times
+type calendarPeriod struct {
+	start, end time.Time
+	name       string
+}
+
+// NewCalendar creates an empty calendar
+func NewCalendar() *Calendar {
+	return &Calendar{dates: make(map[string]string), yearly: make(map[string]string)}
+}
+
+// Add adds the date, in the location of the time, to the calendar
+func (c *Calendar) Add(date time.Time, name string) *Calendar {
+	c.dates[date.Format(calendarDateLayout)] = name
+	return c
+}
+
+// AddYearly adds a date repeated every year to the calendar, such as the Christmas
+func (c *Calendar) AddYearly(month time.Month, day int, name string) *Calendar {
+	c.yearly[fmt.Sprintf("%02d-%02d", month, day)] = name
+	return c
+}
+
+// AddPeriod adds the days touched by the period, from start until end, to the calendar.
+// The days are evaluated in the location of the time queried, so the period may touch
+// different days in different locations
+func (c *Calendar) AddPeriod(start, end time.Time, name string) *Calendar {
+	// Um evento sem duração marca o dia do seu início
+	if !end.After(start) {
+		end = start.Add(time.Nanosecond)
+	}
+
+	c.periods = append(c.periods, calendarPeriod{start: start, end: end, name: name})
+	return c
+}
+
+// Holiday returns whether the date of the time, in its location, is in the calendar, and its name
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	if name, ok := c.dates[t.Format(calendarDateLayout)]; ok {
+		return name, true
+	}
+
+	if name, ok := c.yearly[t.Format(calendarYearlyLayout)]; ok {
+		return name, true
+	}
+
+	if len(c.periods) == 0 {
+		return "", false
+	}
+
+	var (
+		dayStart = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		dayEnd   = dayStart.AddDate(0, 0, 1)
+	)
+
+	for _, period := range c.periods {
+		if period.start.Before(dayEnd) && period.end.After(dayStart) {
+			return period.name, true
+		}
+	}
+
+	return "", false
+}
+
+// LoadCalendar loads a calendar from the file, the files with the .ics extension are read
+// as iCalendar and the other files as a list of dates, as described in ParseCalendarList
+func LoadCalendar(path string) (*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".ics") {
+		return ParseICalendar(file)
+	}
+
+	return ParseCalendarList(file)
+}
+
+// ParseCalendarList parses a list with a date per line, in the format YYYY-MM-DD, or MM-DD for the
+// dates repeated every year, optionally followed by the name of the date. Empty lines and lines
+// starting with # are ignored
+//
+//	# Feriados nacionais
+//	01-01 Confraternização Universal
+//	2024-03-29 Sexta-feira Santa
+func ParseCalendarList(r io.Reader) (*Calendar, error) {
+	var (
+		calendar = NewCalendar()
+		scanner  = bufio.NewScanner(r)
+		line     int
+	)
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		// A data é separada do nome por qualquer espaço em branco, como tabs
+		value, name := text, ""
+		if sep := strings.IndexFunc(text, unicode.IsSpace); sep >= 0 {
+			value, name = text[:sep], strings.TrimSpace(text[sep:])
+		}
+
+		if date, err := time.Parse(calendarDateLayout, value); err == nil {
+			calendar.Add(date, name)
+			continue
+		}
+
+		date, err := time.Parse(calendarYearlyLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date '%s' in the line %d of the calendar", value, line)
+		}
+		calendar.AddYearly(date.Month(), date.Day(), name)
+	}
+
+	return calendar, scanner.Err()
+}
+
+// ParseICalendar parses the events of an iCalendar (RFC 5545), adding the days of each event to the
+// calendar. The all-day events and the floating times, without zone, mark the days of their wall clock,
+// while the events with time in UTC or in the zone of the TZID parameter are added as periods, whose
+// days depend on the location of the time queried. The events repeated yearly are added as dates
+// repeated every year, other recurrence rules are not supported and only the first occurrence of
+// the event is added
+func ParseICalendar(r io.Reader) (*Calendar, error) {
+	lines, err := unfoldICalendar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		calendar = NewCalendar()
+		event    map[string]icalProperty
+	)
+
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = make(map[string]icalProperty)
+		case line == "END:VEVENT":
+			if event == nil {
+				return nil, fmt.Errorf("unexpected END:VEVENT in the calendar")
+			}
+			if err := calendar.addICalendarEvent(event); err != nil {
+				return nil, err
+			}
+			event = nil
+		case event != nil:
+			if name, property, ok := parseICalendarLine(line); ok {
+				event[name] = property
+			}
+		}
+	}
+
+	return calendar, nil
+}
+
+// icalProperty defines the value and the parameters of a property of an iCalendar
+type icalProperty struct {
+	value  string
+	params map[string]string
+}
+
+// parseICalendarLine parses the name, the parameters and the value of a content line of an iCalendar
+func parseICalendarLine(line string) (string, icalProperty, bool) {
+	// O valor começa nos primeiros dois pontos fora de aspas, pois os parâmetros podem conter dois pontos
+	var (
+		quoted bool
+		sep    = -1
+	)
+	for i, char := range line {
+		if char == '"' {
+			quoted = !quoted
+		} else if char == ':' && !quoted {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return "", icalProperty{}, false
+	}
+
+	var (
+		parts    = strings.Split(line[:sep], ";")
+		property = icalProperty{value: line[sep+1:], params: make(map[string]string, len(parts)-1)}
+	)
+
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			property.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return strings.ToUpper(parts[0]), property, true
+}
+
+// icalTimeKind defines the kind of a date or date-time value of an iCalendar
+type icalTimeKind int
+
+const (
+	// icalDate is a date, of an all-day event
+	icalDate icalTimeKind = iota
+	// icalFloating is a date-time without zone, the same wall clock in every location
+	icalFloating
+	// icalZoned is a date-time in UTC or in the zone of the TZID parameter
+	icalZoned
+)
+
+func (c *Calendar) addICalendarEvent(event map[string]icalProperty) error {
+	property, ok := event["DTSTART"]
+	if !ok {
+		return fmt.Errorf("the event '%s' of the calendar has no DTSTART", event["SUMMARY"].value)
+	}
+
+	start, kind, err := parseICalendarTime(property)
+	if err != nil {
+		return err
+	}
+
+	var (
+		name   = icalTextReplacer.Replace(event["SUMMARY"].value)
+		yearly = strings.Contains(strings.ToUpper(event["RRULE"].value), "FREQ=YEARLY")
+		end    time.Time
+	)
+
+	if value, ok := event["DTEND"]; ok {
+		if end, _, err = parseICalendarTime(value); err != nil {
+			return err
+		}
+	}
+
+	if kind == icalZoned && !yearly {
+		c.AddPeriod(start, end, name)
+		return nil
+	}
+
+	// As datas, os horários sem fuso e os eventos anuais com horário, nas datas do seu fuso,
+	// marcam os dias do relógio. O fim é exclusivo, então um fim à meia-noite não marca o dia
+	first, last := calendarDay(start), calendarDay(start)
+	if !end.IsZero() {
+		if last = calendarDay(end); end.Equal(time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())) {
+			last = last.AddDate(0, 0, -1)
+		}
+		if last.Before(first) {
+			last = first
+		}
+	}
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if yearly {
+			c.AddYearly(day.Month(), day.Day(), name)
+		} else {
+			c.Add(day, name)
+		}
+	}
+
+	return nil
+}
+
+// calendarDay returns the date of the time, in its location, at midnight in UTC
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseICalendarTime parses a date or date-time value of an iCalendar. The date-times are in UTC
+// with the Z suffix, in the zone of the TZID parameter, or floating, returned in UTC
+func parseICalendarTime(property icalProperty) (time.Time, icalTimeKind, error) {
+	value := strings.TrimSpace(property.value)
+
+	if strings.EqualFold(property.params["VALUE"], "DATE") || len(value) == len(icalDateLayout) {
+		date, err := time.Parse(icalDateLayout, value)
+		if err != nil {
+			return time.Time{}, icalDate, fmt.Errorf("invalid date '%s' in the calendar", value)
+		}
+		return date, icalDate, nil
+	}
+
+	var (
+		location = time.UTC
+		kind     = icalFloating
+	)
+
+	if utc := strings.TrimSuffix(value, "Z"); utc != value {
+		value, kind = utc, icalZoned
+	} else if tzid := property.params["TZID"]; tzid != "" {
+		loc, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+		if err != nil {
+			return time.Time{}, icalZoned, fmt.Errorf("unknown time zone '%s' in the calendar: %w", tzid, err)
+		}
+		location, kind = loc, icalZoned
+	}
+
+	t, err := time.ParseInLocation(icalDateTimeLayout, value, location)
+	if err != nil {
+		return time.Time{}, kind, fmt.Errorf("invalid date-time '%s' in the calendar", property.value)
+	}
+
+	return t, kind, nil
+}
+
+// unfoldICalendar returns the lines of the iCalendar, joining the lines folded in many lines
+func unfoldICalendar(r io.Reader) ([]string, error) {
+	var (
+		lines   []string
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
@@ -0,0 +1,210 @@
+package outis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// icalendar wraps the events in an iCalendar with CRLF line breaks
+func icalendar(events ...string) string {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//outis//test//PT"}
+	for _, event := range events {
+		lines = append(lines, "BEGIN:VEVENT")
+		lines = append(lines, strings.Split(event, "\n")...)
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// holidays returns the dates of the period, in the location, that are in the calendar
+func holidays(calendar *Calendar, from, to string, loc *time.Location) []string {
+	var (
+		start, _ = time.ParseInLocation(calendarDateLayout, from, loc)
+		end, _   = time.ParseInLocation(calendarDateLayout, to, loc)
+		dates    = make([]string, 0)
+	)
+
+	for day := start.Add(12 * time.Hour); day.Before(end.Add(24 * time.Hour)); day = day.AddDate(0, 0, 1) {
+		if _, ok := calendar.Holiday(day); ok {
+			dates = append(dates, day.Format(calendarDateLayout))
+		}
+	}
+
+	return dates
+}
+
+func TestParseCalendarList(t *testing.T) {
+	calendar, err := ParseCalendarList(strings.NewReader(strings.Join([]string{
+		"# Feriados nacionais",
+		"",
+		"01-01 Confraternização Universal",
+		"2024-03-29\tSexta-feira Santa",
+		"  2024-05-30   \t Corpus Christi  ",
+		"12-25",
+	}, "\n")))
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		date, name string
+	}{
+		{date: "2024-01-01", name: "Confraternização Universal"},
+		{date: "2031-01-01", name: "Confraternização Universal"},
+		{date: "2024-03-29", name: "Sexta-feira Santa"},
+		{date: "2024-05-30", name: "Corpus Christi"},
+		{date: "2024-12-25", name: ""},
+	} {
+		date, _ := time.Parse(calendarDateLayout, test.date)
+		name, ok := calendar.Holiday(date)
+		assert.True(t, ok, test.date)
+		assert.Equal(t, test.name, name, test.date)
+	}
+
+	_, ok := calendar.Holiday(time.Date(2025, 3, 29, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "the dates with year must not repeat")
+}
+
+func TestParseCalendarListInvalid(t *testing.T) {
+	_, err := ParseCalendarList(strings.NewReader("01-01 Ano Novo\n2024-13-01\tInválida"))
+	assert.EqualError(t, err, "invalid date '2024-13-01' in the line 2 of the calendar")
+}
+
+func TestParseICalendar(t *testing.T) {
+	var (
+		saoPaulo = loadLocation(t, "America/Sao_Paulo")
+		tokyo    = loadLocation(t, "Asia/Tokyo")
+	)
+
+	for _, test := range []struct {
+		name     string
+		event    string
+		from, to string
+		loc      *time.Location
+		want     []string
+	}{
+		{
+			name:  "all-day event",
+			event: "DTSTART;VALUE=DATE:20240329\nSUMMARY:Sexta-feira Santa",
+			from:  "2024-03-28", to: "2024-03-30", loc: time.UTC,
+			want: []string{"2024-03-29"},
+		},
+		{
+			name:  "all-day event with exclusive end",
+			event: "DTSTART;VALUE=DATE:20240212\nDTEND;VALUE=DATE:20240214\nSUMMARY:Carnaval",
+			from:  "2024-02-11", to: "2024-02-15", loc: time.UTC,
+			want: []string{"2024-02-12", "2024-02-13"},
+		},
+		{
+			name:  "all-day event keeps the wall clock in every location",
+			event: "DTSTART:20240329\nSUMMARY:Sexta-feira Santa",
+			from:  "2024-03-28", to: "2024-03-30", loc: tokyo,
+			want: []string{"2024-03-29"},
+		},
+		{
+			name:  "yearly event",
+			event: "DTSTART;VALUE=DATE:20201225\nRRULE:FREQ=YEARLY\nSUMMARY:Natal",
+			from:  "2030-12-24", to: "2030-12-26", loc: time.UTC,
+			want: []string{"2030-12-25"},
+		},
+		{
+			name:  "UTC time in the location of the query",
+			event: "DTSTART:20240101T020000Z\nDTEND:20240101T023000Z\nSUMMARY:Manutenção",
+			from:  "2023-12-30", to: "2024-01-02", loc: saoPaulo,
+			want: []string{"2023-12-31"},
+		},
+		{
+			name:  "UTC time in UTC",
+			event: "DTSTART:20240101T020000Z\nDTEND:20240101T040000Z\nSUMMARY:Manutenção",
+			from:  "2023-12-30", to: "2024-01-02", loc: time.UTC,
+			want: []string{"2024-01-01"},
+		},
+		{
+			name:  "time in the zone of the TZID",
+			event: "DTSTART;TZID=America/Sao_Paulo:20240325T220000\nDTEND;TZID=America/Sao_Paulo:20240325T230000\nSUMMARY:Janela",
+			from:  "2024-03-24", to: "2024-03-27", loc: time.UTC,
+			want: []string{"2024-03-26"},
+		},
+		{
+			name:  "quoted TZID",
+			event: "DTSTART;TZID=\"America/Sao_Paulo\":20240325T100000\nSUMMARY:Reunião",
+			from:  "2024-03-24", to: "2024-03-27", loc: saoPaulo,
+			want: []string{"2024-03-25"},
+		},
+		{
+			name:  "event crossing midnight",
+			event: "DTSTART:20240325T220000Z\nDTEND:20240326T020000Z\nSUMMARY:Janela",
+			from:  "2024-03-24", to: "2024-03-28", loc: time.UTC,
+			want: []string{"2024-03-25", "2024-03-26"},
+		},
+		{
+			name:  "event ending at midnight",
+			event: "DTSTART:20240325T220000Z\nDTEND:20240326T000000Z\nSUMMARY:Janela",
+			from:  "2024-03-24", to: "2024-03-28", loc: time.UTC,
+			want: []string{"2024-03-25"},
+		},
+		{
+			name:  "floating time keeps the wall clock in every location",
+			event: "DTSTART:20240325T230000\nDTEND:20240326T010000\nSUMMARY:Janela",
+			from:  "2024-03-24", to: "2024-03-28", loc: tokyo,
+			want: []string{"2024-03-25", "2024-03-26"},
+		},
+		{
+			name:  "yearly event with time in the dates of its zone",
+			event: "DTSTART;TZID=Asia/Tokyo:20200101T000000\nRRULE:FREQ=YEARLY\nSUMMARY:Ano Novo",
+			from:  "2030-12-31", to: "2031-01-02", loc: time.UTC,
+			want: []string{"2031-01-01"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			calendar, err := ParseICalendar(strings.NewReader(icalendar(test.event)))
+			require.NoError(t, err)
+			assert.Equal(t, test.want, holidays(calendar, test.from, test.to, test.loc))
+		})
+	}
+}
+
+func TestParseICalendarSummary(t *testing.T) {
+	calendar, err := ParseICalendar(strings.NewReader(icalendar(
+		"DTSTART;VALUE=DATE:20240101\nSUMMARY:Confraternização\\, Universal\\; feriado\n  nacional",
+	)))
+	require.NoError(t, err)
+
+	name, ok := calendar.Holiday(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Confraternização, Universal; feriado nacional", name)
+}
+
+func TestParseICalendarInvalid(t *testing.T) {
+	for _, test := range []struct {
+		name, calendar, err string
+	}{
+		{name: "invalid date", calendar: icalendar("DTSTART;VALUE=DATE:2024-01-01"), err: "invalid date '2024-01-01' in the calendar"},
+		{name: "invalid date-time", calendar: icalendar("DTSTART:20240101T25000Z"), err: "invalid date-time '20240101T25000Z' in the calendar"},
+		{name: "missing start", calendar: icalendar("SUMMARY:Sem data"), err: "the event 'Sem data' of the calendar has no DTSTART"},
+		{name: "unknown zone", calendar: icalendar("DTSTART;TZID=Mars/Olympus:20240101T100000"), err: "unknown time zone 'Mars/Olympus' in the calendar"},
+		{name: "unexpected end", calendar: "BEGIN:VCALENDAR\r\nEND:VEVENT\r\n", err: "unexpected END:VEVENT in the calendar"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseICalendar(strings.NewReader(test.calendar))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestCalendarAddPeriod(t *testing.T) {
+	var (
+		saoPaulo = loadLocation(t, "America/Sao_Paulo")
+		instant  = time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+		calendar = NewCalendar().AddPeriod(instant, instant, "Instante")
+	)
+
+	// Um período sem duração marca o dia do seu início em cada local
+	assert.Equal(t, []string{"2024-01-01"}, holidays(calendar, "2023-12-30", "2024-01-02", time.UTC))
+	assert.Equal(t, []string{"2023-12-31"}, holidays(calendar, "2023-12-30", "2024-01-02", saoPaulo))
+}
@@ -0,0 +1,136 @@
+package outis
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// catchUpMaxSlots is the maximum amount of missed slots searched, avoiding
+// long searches when a short interval was missed for a long period
+const catchUpMaxSlots = 100000
+
+type catchUpMode string
+
+const (
+	catchUpSkip catchUpMode = "skip"
+	catchUpOnce catchUpMode = "once"
+	catchUpAll  catchUpMode = "all"
+)
+
+// CatchUpPolicy defines what happens with the executions missed while the watcher was down.
+// The missed executions are detected on startup from the last successful execution recorded in the history.
+// The replicas sharing the history detect the same missed slots, with DistributedLock the catch-up of the
+// routine is claimed by a single replica until the next slot, without it every replica catches up the slots
+type CatchUpPolicy struct {
+	mode  catchUpMode
+	limit int
+}
+
+// CatchUpSkip ignores the missed executions, only logging them
+func CatchUpSkip() CatchUpPolicy {
+	return CatchUpPolicy{mode: catchUpSkip}
+}
+
+// CatchUpOnce runs a single execution for the most recent missed slot
+func CatchUpOnce() CatchUpPolicy {
+	return CatchUpPolicy{mode: catchUpOnce, limit: 1}
+}
+
+// CatchUpAll runs an execution for each missed slot, in chronological order, up to
+// limit executions. When more slots were missed, only the most recent ones are executed
+func CatchUpAll(limit int) CatchUpPolicy {
+	return CatchUpPolicy{mode: catchUpAll, limit: limit}
+}
+
+// String returns the name of the policy
+func (p CatchUpPolicy) String() string {
+	if p.mode == catchUpAll {
+		return fmt.Sprintf("%s(%d)", p.mode, p.limit)
+	}
+	return string(p.mode)
+}
+
+func (p CatchUpPolicy) validate() error {
+	if p.mode == catchUpAll && p.limit <= 0 {
+		return errors.New("the catch-up policy limit must be greater than zero")
+	}
+
+	return nil
+}
+
+// lastScheduledAt returns the most recent scheduled time of the executions of the schedule of the routine
+// kept in the history with the outcome, or with any outcome when it is empty. It returns the zero time when
+// the routine was never executed. The triggered executions are ignored, so that an execution out of the
+// schedule does not hide the missed slots, and the catch-ups are compared by their scheduled time
+func (ctx *ContextImpl) lastScheduledAt(outcome ExecutionOutcome) (time.Time, error) {
+	executions, err := ctx.Watcher.history.store.Query(ctx.context, ExecutionQuery{
+		RoutineID:         ctx.routineID,
+		Outcome:           outcome,
+		Scheduled:         true,
+		SortByScheduledAt: true,
+		Limit:             1,
+	})
+	if err != nil || len(executions) == 0 {
+		return time.Time{}, err
+	}
+
+	return executions[0].ScheduledAt, nil
+}
+
+// missedSlots returns the slots of the routine missed between the last successful
+// execution and now, limited by the catch-up policy
+func (ctx *ContextImpl) missedSlots(last, now time.Time) []time.Time {
+	var (
+		slots  []time.Time
+		missed int
+	)
+
+	for slot := ctx.next(last); !slot.IsZero() && slot.Before(now) && missed < catchUpMaxSlots; slot = ctx.next(slot) {
+		if !ctx.inPeriod(slot) {
+			continue
+		}
+
+		missed++
+		if slots = append(slots, slot); len(slots) > ctx.catchUpPolicy.limit {
+			slots = slots[1:]
+		}
+	}
+
+	if missed == 0 {
+		return nil
+	}
+
+	ctx.LogWarn("Executions missed while the watcher was down", LogFields{
+		"last_execution": last.String(),
+		"missed":         missed,
+		"catch_up":       ctx.catchUpPolicy.String(),
+		"executions":     len(slots),
+	})
+
+	return slots
+}
+
+// inPeriod returns whether the time is inside the days, hours and minutes of execution of the routine
+func (ctx *ContextImpl) inPeriod(t time.Time) bool {
+	t = t.In(ctx.Location)
+
+	return ctx.dayAllowed(t) &&
+		!(ctx.period.hourSet && ctx.mustWait(t.Hour(), ctx.period.startHour, ctx.period.endHour)) &&
+		!(ctx.period.minuteSet && ctx.mustWait(t.Minute(), ctx.period.startMinute, ctx.period.endMinute))
+}
+
+// catchUp executes the slots missed since the last successful execution, according to the catch-up policy
+func (r *routine) catchUp(last time.Time) {
+	if last.IsZero() {
+		return
+	}
+
+	for _, slot := range r.ctx.missedSlots(last, r.ctx.Watcher.now()) {
+		if r.ctx.context.Err() != nil {
+			return
+		}
+
+		r.dispatch(tick{scheduledAt: slot, catchUp: true})
+	}
+}
@@ -0,0 +1,202 @@
+package outis
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCatchUpStore creates a history whose last successful execution of the routine
+// was scheduled 4m30s ago, so a routine executed every minute missed 4 slots
+func newCatchUpStore(t *testing.T) (ExecutionStore, []time.Time) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "executions.jsonl"))
+	require.NoError(t, err)
+
+	last := time.Now().Add(-4*time.Minute - 30*time.Second).Truncate(time.Millisecond)
+	require.NoError(t, store.Save(context.Background(), Execution{
+		RoutineID:   "routine",
+		ScheduledAt: last,
+		StartedAt:   last,
+		Outcome:     OutcomeSuccess,
+	}))
+
+	slots := make([]time.Time, 0, 4)
+	for i := 1; i <= 4; i++ {
+		slots = append(slots, last.Add(time.Duration(i)*time.Minute))
+	}
+
+	return store, slots
+}
+
+// runCatchUp starts the routine in a new watcher and returns the slots caught up by it
+func runCatchUp(t *testing.T, store ExecutionStore, policy CatchUpPolicy, script func(Context) error, opts ...WatcherOption) []time.Time {
+	t.Helper()
+
+	var (
+		impl  = newExitRecorder()
+		watch = newTestWatcher(t, append([]WatcherOption{Impl(impl), History(store, 0)}, opts...)...)
+		ran   = make(chan time.Time, 16)
+	)
+
+	watch.Go(
+		WithID("routine"),
+		WithName("routine"),
+		WithInterval(time.Minute),
+		WithCatchUp(policy),
+		WithScript(func(ctx Context) error {
+			ran <- ctx.ScheduledAt()
+			return script(ctx)
+		}),
+	)
+
+	// As execuções perdidas são despachadas antes da espera do primeiro intervalo
+	time.Sleep(200 * time.Millisecond)
+	_, err := watch.Shutdown(context.Background())
+	require.NoError(t, err)
+	impl.wait(t)
+	close(ran)
+
+	slots := make([]time.Time, 0)
+	for slot := range ran {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Before(slots[j]) })
+
+	return slots
+}
+
+func TestCatchUpPolicies(t *testing.T) {
+	success := func(Context) error { return nil }
+
+	for _, test := range []struct {
+		name   string
+		policy CatchUpPolicy
+		want   func(slots []time.Time) []time.Time
+	}{
+		{name: "skip", policy: CatchUpSkip(), want: func([]time.Time) []time.Time { return []time.Time{} }},
+		{name: "once", policy: CatchUpOnce(), want: func(slots []time.Time) []time.Time { return slots[3:] }},
+		{name: "all up to the limit", policy: CatchUpAll(2), want: func(slots []time.Time) []time.Time { return slots[2:] }},
+		{name: "all", policy: CatchUpAll(10), want: func(slots []time.Time) []time.Time { return slots }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			store, slots := newCatchUpStore(t)
+
+			ran := runCatchUp(t, store, test.policy, success)
+			assert.Equal(t, unixNanos(test.want(slots)), unixNanos(ran))
+
+			executions, err := store.Query(context.Background(), ExecutionQuery{RoutineID: "routine"})
+			require.NoError(t, err)
+			for _, execution := range executions[:len(executions)-1] {
+				assert.True(t, execution.CatchUp, "the execution of the missed slot must be marked as catch-up")
+			}
+		})
+	}
+}
+
+func TestCatchUpNeverExecuted(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "executions.jsonl"))
+	require.NoError(t, err)
+
+	assert.Empty(t, runCatchUp(t, store, CatchUpAll(10), func(Context) error { return nil }))
+}
+
+func TestCatchUpIgnoresTriggeredExecutions(t *testing.T) {
+	store, slots := newCatchUpStore(t)
+
+	// Uma execução manual e uma execução perdida de um horário anterior, ambas iniciadas após o último
+	// horário agendado, não escondem os horários perdidos
+	for _, execution := range []Execution{
+		{RoutineID: "routine", ScheduledAt: slots[3], StartedAt: slots[3], Outcome: OutcomeSuccess, Triggered: true},
+		{RoutineID: "routine", ScheduledAt: slots[0].Add(-time.Hour), StartedAt: slots[3], Outcome: OutcomeSuccess, CatchUp: true},
+	} {
+		require.NoError(t, store.Save(context.Background(), execution))
+	}
+
+	ran := runCatchUp(t, store, CatchUpAll(10), func(Context) error { return nil })
+	assert.Equal(t, unixNanos(slots), unixNanos(ran))
+}
+
+func TestCatchUpClaimedByReplica(t *testing.T) {
+	locker, err := NewFileLocker(t.TempDir())
+	require.NoError(t, err)
+
+	var (
+		slot = time.Now().Add(-time.Hour).Truncate(time.Minute)
+		runs int
+	)
+
+	// catchUp executes the missed slot in a replica of the watcher. As the next slot
+	// of the schedule has passed, the lock of the routine is released after the execution
+	catchUp := func(watch *Watch, slot time.Time) {
+		ctx, err := watch.NewContext(WithID("routine"), WithName("routine"), WithInterval(time.Minute), WithScript(func(Context) error {
+			runs++
+			return errors.New("failure")
+		}))
+		require.NoError(t, err)
+
+		exec := ctx.newExecution()
+		defer exec.contextCancelFunc()
+		exec.scheduledAt, exec.catchUp = slot, true
+		_ = exec.execute()
+	}
+
+	var (
+		first  = newTestWatcher(t, DistributedLock(locker, time.Minute))
+		second = newTestWatcher(t, DistributedLock(locker, time.Minute))
+	)
+
+	catchUp(first, slot)
+	catchUp(first, slot.Add(time.Minute))
+	assert.Equal(t, 2, runs, "the replica that holds the claim catches up every slot")
+
+	// O catch-up reivindicado pela primeira réplica não é executado pela segunda, mesmo com falha
+	catchUp(second, slot)
+	catchUp(second, slot.Add(2*time.Minute))
+	assert.Equal(t, 2, runs, "the catch-up claimed by another replica must be skipped")
+
+	// Uma única reivindicação é mantida para a rotina, com validade até o próximo horário
+	entries, err := filepath.Glob(filepath.Join(locker.(*fileLocker).dir, "*.lock"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	lease, err := locker.(*fileLocker).read("routine@catch-up")
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), lease.ExpiresAt, time.Minute+time.Second)
+
+	// Sem o lock distribuído, cada réplica executa os horários perdidos
+	catchUp(newTestWatcher(t), slot)
+	assert.Equal(t, 3, runs)
+}
+
+func TestCatchUpValidation(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "executions.jsonl"))
+	require.NoError(t, err)
+
+	script := WithScript(func(Context) error { return nil })
+
+	_, err = newTestWatcher(t, History(store, 0)).NewContext(WithName("routine"), WithInterval(time.Minute), WithCatchUp(CatchUpAll(0)), script)
+	assert.Error(t, err, "the limit of CatchUpAll must be positive")
+
+	_, err = newTestWatcher(t).NewContext(WithName("routine"), WithInterval(time.Minute), WithCatchUp(CatchUpOnce()), script)
+	assert.Error(t, err, "the catch-up requires the history")
+
+	assert.Equal(t, "all(3)", CatchUpAll(3).String())
+	assert.Equal(t, "once", CatchUpOnce().String())
+	assert.Equal(t, "skip", CatchUpSkip().String())
+}
+
+// unixNanos converts the times to nanoseconds, comparing them without the location and monotonic clock
+func unixNanos(times []time.Time) []int64 {
+	result := make([]int64, 0, len(times))
+	for _, t := range times {
+		result = append(result, t.UnixNano())
+	}
+	return result
+}
@@ -0,0 +1,164 @@
+package outis
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// IClock defines the source of time used to schedule and measure the routines,
+// it allows the schedules to be tested with a fake clock, such as outistest.FakeClock
+type IClock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) ITimer
+}
+
+// ITimer defines a timer created by a clock, which sends the current time on its channel when it expires
+type ITimer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// realClock implements the clock with the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) ITimer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct{ timer *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.timer.C }
+
+func (t realTimer) Stop() bool { return t.timer.Stop() }
+
+// ClockTracker is implemented by the clocks that only move while the watcher waits for them, such as
+// outistest.FakeClock. The watcher reports when its goroutines, including the executions of the scripts,
+// are running and when they wait, so that the clock fires a timer only after the goroutines woken by the
+// previous timer are waiting again
+type ClockTracker interface {
+	// Running marks a goroutine of the watcher as running
+	Running()
+	// Waiting marks a goroutine of the watcher as waiting
+	Waiting()
+	// NewTrackedTimer creates a timer waited by a goroutine of the watcher, the goroutine is marked as
+	// running when the timer fires, and as waiting again when the timer is stopped before being received
+	NewTrackedTimer(d time.Duration) ITimer
+}
+
+// now returns the current time of the clock of the watcher
+func (watch *Watch) now() time.Time {
+	return watch.getClock().Now()
+}
+
+// getClock returns the clock of the watcher, by default the clock of the time package
+func (watch *Watch) getClock() IClock {
+	if watch.clock == nil {
+		return realClock{}
+	}
+	return watch.clock
+}
+
+// newTimer creates a timer of the clock of the watcher, waited by one of the goroutines of the watcher
+func (watch *Watch) newTimer(d time.Duration) ITimer {
+	if tracker, ok := watch.clock.(ClockTracker); ok {
+		return tracker.NewTrackedTimer(d)
+	}
+	return watch.getClock().NewTimer(d)
+}
+
+// running marks a goroutine of the watcher as running when the clock tracks the watcher
+func (watch *Watch) running() {
+	if tracker, ok := watch.clock.(ClockTracker); ok {
+		tracker.Running()
+	}
+}
+
+// waiting marks a goroutine of the watcher as waiting when the clock tracks the watcher
+func (watch *Watch) waiting() {
+	if tracker, ok := watch.clock.(ClockTracker); ok {
+		tracker.Waiting()
+	}
+}
+
+// since returns the time elapsed since the given time according to the clock of the watcher
+func (watch *Watch) since(t time.Time) time.Duration {
+	return watch.now().Sub(t)
+}
+
+// clockContext is a context whose deadline is controlled by a clock. It keeps its own done channel,
+// so the contexts derived from it are cancelled with its error instead of the error of its parent
+type clockContext struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// withClockTimeout returns a copy of the parent context that is cancelled when the timeout,
+// measured by the clock of the watcher, elapses, reporting context.DeadlineExceeded as its error
+func withClockTimeout(parent context.Context, watch *Watch, timeout time.Duration) (context.Context, context.CancelFunc) {
+	var (
+		ctx      = &clockContext{Context: parent, deadline: watch.now().Add(timeout), done: make(chan struct{})}
+		cancel   = make(chan struct{})
+		once     sync.Once
+		timer    = watch.newTimer(timeout)
+		finished = make(chan struct{})
+	)
+
+	watch.running()
+	go func() {
+		defer close(finished)
+		defer watch.waiting()
+		defer timer.Stop()
+
+		watch.waiting()
+		select {
+		case <-parent.Done():
+			watch.running()
+			ctx.finish(parent.Err())
+		case <-timer.C():
+			ctx.finish(context.DeadlineExceeded)
+		case <-cancel:
+			watch.running()
+			ctx.finish(context.Canceled)
+		}
+	}()
+
+	return ctx, func() {
+		once.Do(func() { close(cancel) })
+		<-finished
+	}
+}
+
+// finish defines the error of the context and closes its done channel
+func (ctx *clockContext) finish(err error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	ctx.err = err
+	close(ctx.done)
+}
+
+// Deadline returns the time when the context expires, or the deadline of the parent when it is earlier
+func (ctx *clockContext) Deadline() (time.Time, bool) {
+	if deadline, ok := ctx.Context.Deadline(); ok && deadline.Before(ctx.deadline) {
+		return deadline, true
+	}
+	return ctx.deadline, true
+}
+
+// Done returns the channel closed when the context is cancelled or expires
+func (ctx *clockContext) Done() <-chan struct{} {
+	return ctx.done
+}
+
+// Err returns context.DeadlineExceeded when the context expired and context.Canceled when it was cancelled
+func (ctx *clockContext) Err() error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	return ctx.err
+}
@@ -0,0 +1,232 @@
+package outis_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+	outismocks "github.com/Brisanet/outis/mocks"
+	"github.com/Brisanet/outis/outistest"
+)
+
+var start = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+// newClockWatcher creates a watcher using the fake clock, shut down at the end of the test
+func newClockWatcher(t *testing.T, clock outis.IClock, opts ...outis.WatcherOption) *outis.Watch {
+	watch := outis.Watcher("watcher", "watcher", append([]outis.WatcherOption{outis.Logger(outistest.NewLogRecorder()), outis.Clock(clock)}, opts...)...)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+	return watch
+}
+
+func TestLockRefreshUsesClock(t *testing.T) {
+	var (
+		clock  = outistest.NewFakeClock(start)
+		locker = outismocks.NewLocker(t)
+		watch  = newClockWatcher(t, clock, outis.DistributedLock(locker, 3*time.Minute))
+	)
+
+	locker.EXPECT().Lock(mock.Anything, "routine", mock.Anything, 3*time.Minute).Return(true, nil)
+	locker.EXPECT().Refresh(mock.Anything, "routine", mock.Anything, mock.Anything).Return(false, nil)
+	locker.EXPECT().Unlock(mock.Anything, "routine", mock.Anything).Return(nil).Maybe()
+
+	ctx, err := watch.NewContext(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithScript(func(ctx outis.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	require.NoError(t, err)
+
+	result := make(chan error, 1)
+	go func() { result <- ctx.Execute() }()
+
+	// O lease é renovado a cada terço do ttl, segundo o relógio do watcher
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	select {
+	case err := <-result:
+		assert.True(t, errors.Is(err, context.Canceled), "unexpected error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lost lock did not cancel the execution")
+	}
+}
+
+func TestLeaderElectionUsesClock(t *testing.T) {
+	var (
+		clock  = outistest.NewFakeClock(start)
+		locker = outismocks.NewLocker(t)
+	)
+
+	locker.EXPECT().Lock(mock.Anything, "watcher", mock.Anything, 3*time.Minute).Return(false, nil).Once()
+	locker.EXPECT().Lock(mock.Anything, "watcher", mock.Anything, 3*time.Minute).Return(true, nil).Once()
+	locker.EXPECT().Refresh(mock.Anything, "watcher", mock.Anything, 3*time.Minute).Return(true, nil).Maybe()
+	locker.EXPECT().Unlock(mock.Anything, "watcher", mock.Anything).Return(nil).Maybe()
+
+	watch := newClockWatcher(t, clock, outis.LeaderElection(locker, 3*time.Minute))
+
+	clock.BlockUntil(1)
+	assert.False(t, watch.IsLeader())
+
+	clock.Advance(time.Minute)
+	assert.Eventually(t, watch.IsLeader, 5*time.Second, time.Millisecond)
+}
+
+func TestIndicatorUsesClock(t *testing.T) {
+	watch := newClockWatcher(t, outistest.NewFakeClock(start))
+
+	ctx, err := watch.NewContext(outis.WithID("routine"), outis.WithName("routine"), outis.WithScript(func(outis.Context) error { return nil }))
+	require.NoError(t, err)
+
+	assert.Equal(t, start, ctx.NewIndicator("processed").GetCreatedAt())
+}
+
+func TestPreviousScheduledAt(t *testing.T) {
+	store, err := outis.NewFileStore(filepath.Join(t.TempDir(), "executions.jsonl"))
+	require.NoError(t, err)
+
+	// A última execução agendada falhou e foi seguida por uma execução manual
+	for _, execution := range []outis.Execution{
+		{RoutineID: "routine", ScheduledAt: start.Add(-3 * time.Minute), StartedAt: start.Add(-3 * time.Minute), Outcome: outis.OutcomeSuccess},
+		{RoutineID: "routine", ScheduledAt: start.Add(-2 * time.Minute), StartedAt: start.Add(-2 * time.Minute), Outcome: outis.OutcomeError},
+		{RoutineID: "routine", ScheduledAt: start.Add(-time.Minute), StartedAt: start.Add(-time.Minute), Outcome: outis.OutcomeSuccess, Triggered: true},
+	} {
+		require.NoError(t, store.Save(context.Background(), execution))
+	}
+
+	var (
+		clock = outistest.NewFakeClock(start)
+		watch = newClockWatcher(t, clock, outis.History(store, 0))
+
+		mu   sync.Mutex
+		runs [][2]time.Time
+	)
+
+	watch.Go(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithInterval(time.Minute),
+		outis.WithScript(func(ctx outis.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			runs = append(runs, [2]time.Time{ctx.ScheduledAt(), ctx.PreviousScheduledAt()})
+			if len(runs) == 1 {
+				return errors.New("failure")
+			}
+			return nil
+		}),
+	)
+
+	executed := func(n int) func() bool {
+		return func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(runs) == n
+		}
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	clock.Advance(30 * time.Second)
+	require.NoError(t, watch.TriggerNow("routine", nil))
+	require.Eventually(t, executed(2), 5*time.Second, time.Millisecond)
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Second)
+	require.True(t, executed(3)())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Na inicialização, o horário anterior é o da última execução agendada do histórico, mesmo com falha
+	assert.Equal(t, [2]time.Time{start.Add(time.Minute), start.Add(-2 * time.Minute)}, toUTC(runs[0]))
+	// A execução manual recebe o horário da execução agendada anterior
+	assert.Equal(t, [2]time.Time{start.Add(90 * time.Second), start.Add(time.Minute)}, toUTC(runs[1]))
+	// Durante a execução, a execução com falha é a anterior e a execução manual é ignorada
+	assert.Equal(t, [2]time.Time{start.Add(2 * time.Minute), start.Add(time.Minute)}, toUTC(runs[2]))
+}
+
+// toUTC converts the times to UTC, comparing them without the location and the monotonic clock
+func toUTC(times [2]time.Time) [2]time.Time {
+	return [2]time.Time{times[0].UTC(), times[1].UTC()}
+}
+
+func TestPauseResumeTriggerNow(t *testing.T) {
+	var (
+		clock    = outistest.NewFakeClock(start)
+		recorder = outistest.NewRecorder(nil)
+		watch    = newClockWatcher(t, clock, outis.Impl(recorder))
+	)
+
+	watch.Go(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithInterval(time.Minute),
+		outis.WithScript(func(outis.Context) error { return nil }),
+	)
+
+	executed := func(n int) func() bool {
+		return func() bool { return len(recorder.Metrics()) == n }
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	require.Eventually(t, executed(1), 5*time.Second, time.Millisecond)
+
+	// Enquanto pausada, os horários agendados são ignorados
+	require.NoError(t, watch.Pause("routine"))
+	snapshot, _ := watch.Routine("routine")
+	assert.True(t, snapshot.Paused)
+
+	clock.BlockUntil(1)
+	clock.Advance(2 * time.Minute)
+	clock.BlockUntil(1)
+	assert.Len(t, recorder.Metrics(), 1)
+
+	snapshot, _ = watch.Routine("routine")
+	assert.Equal(t, start.Add(4*time.Minute), snapshot.NextRun.UTC())
+
+	// A execução manual é feita mesmo com a rotina pausada
+	require.NoError(t, watch.TriggerNow("routine", outis.Metadata{"source": "admin"}))
+	require.Eventually(t, executed(2), 5*time.Second, time.Millisecond)
+
+	// Ao retomar, a rotina volta a seguir o agendamento
+	require.NoError(t, watch.Resume("routine"))
+	snapshot, _ = watch.Routine("routine")
+	assert.False(t, snapshot.Paused)
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	require.Eventually(t, executed(3), 5*time.Second, time.Millisecond)
+
+	metrics := recorder.Metrics()
+	assert.Equal(t, start.Add(time.Minute), metrics[0].ScheduledAt.UTC())
+	assert.False(t, metrics[0].Triggered)
+
+	assert.Equal(t, start.Add(3*time.Minute), metrics[1].ScheduledAt.UTC())
+	assert.True(t, metrics[1].Triggered)
+	assert.Equal(t, "admin", metrics[1].Metadata["source"])
+
+	assert.Equal(t, start.Add(4*time.Minute), metrics[2].ScheduledAt.UTC())
+	assert.False(t, metrics[2].Triggered)
+}
+
+func TestPauseResumeTriggerNowUnknownRoutine(t *testing.T) {
+	watch := newClockWatcher(t, outistest.NewFakeClock(start))
+
+	assert.ErrorIs(t, watch.Pause("unknown"), outis.ErrRoutineNotFound)
+	assert.ErrorIs(t, watch.Resume("unknown"), outis.ErrRoutineNotFound)
+	assert.ErrorIs(t, watch.TriggerNow("unknown", nil), outis.ErrRoutineNotFound)
+}
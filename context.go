@@ -3,6 +3,7 @@ package outis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -11,6 +12,10 @@ type period struct {
 	startHour, endHour     uint
 	minuteSet              bool
 	startMinute, endMinute uint
+	weekdays               []time.Weekday
+	monthDays              []int
+	months                 []time.Month
+	holidays               *Calendar
 }
 
 // Context defines the data structure of the routine context.
@@ -30,10 +35,16 @@ type Context interface {
 	LogWarn(msg string, fields ...LogFields)
 	AddSingleMetadata(key string, args interface{}) Context
 	AddMetadata(metadata Metadata) Context
+	NewIndicator(key string) *Indicator
+	NewHistogram(key string, opts ...HistogramOption) *Histogram
+	Retry(retries int8) *Retrier
 
 	Name() string
 	RoutineID() ID
 	ID() ID
+	Attempt() int
+	ScheduledAt() time.Time
+	PreviousScheduledAt() time.Time
 }
 
 // ContextImpl implements context interface
@@ -44,18 +55,33 @@ type ContextImpl struct {
 	Desc      string
 	period    period
 	Interval  time.Duration
+	Cron      string
+	Location  *time.Location
 	Path      string
 	RunAt     time.Time
 	Watcher   Watch
 
 	// Reminder: If new fields are added, change context.Copy function accordingly.
 	script                         func(Context) error
+	schedule                       *cronSchedule
 	metadata                       Metadata
 	latency                        time.Duration
 	notUseLoop                     bool
 	executeFirstTimeBeforeInterval bool
-	histogram                      []*Histogram
-	indicator                      []*Indicator
+	timeout                        time.Duration
+	retryPolicy                    *RetryPolicy
+	restartPolicy                  *RestartPolicy
+	attempt                        int
+	overlap                        OverlapPolicy
+	overlapMetric                  OverlapMetric
+	catchUpPolicy                  *CatchUpPolicy
+	jitter                         *Jitter
+	startOffset                    time.Duration
+	scheduledAt                    time.Time
+	previousScheduledAt            time.Time
+	catchUp                        bool
+	triggered                      bool
+	measures                       *measures
 	log                            ILogger
 	context                        context.Context //nolint:containedctx
 	contextCancelFunc              context.CancelFunc
@@ -103,16 +129,31 @@ func (ctx *ContextImpl) copy(baseCtxIn ...context.Context) *ContextImpl {
 		Desc:                           ctx.Desc,
 		period:                         ctx.period,
 		Interval:                       ctx.Interval,
+		Cron:                           ctx.Cron,
+		Location:                       ctx.Location,
 		Path:                           ctx.Path,
 		RunAt:                          ctx.RunAt,
 		Watcher:                        ctx.Watcher,
 		script:                         ctx.script,
+		schedule:                       ctx.schedule,
 		metadata:                       ctx.metadata,
 		latency:                        ctx.latency,
 		notUseLoop:                     ctx.notUseLoop,
 		executeFirstTimeBeforeInterval: ctx.executeFirstTimeBeforeInterval,
-		histogram:                      make([]*Histogram, 0),
-		indicator:                      make([]*Indicator, 0),
+		timeout:                        ctx.timeout,
+		retryPolicy:                    ctx.retryPolicy,
+		restartPolicy:                  ctx.restartPolicy,
+		attempt:                        ctx.attempt,
+		overlap:                        ctx.overlap,
+		overlapMetric:                  ctx.overlapMetric,
+		catchUpPolicy:                  ctx.catchUpPolicy,
+		jitter:                         ctx.jitter,
+		startOffset:                    ctx.startOffset,
+		scheduledAt:                    ctx.scheduledAt,
+		previousScheduledAt:            ctx.previousScheduledAt,
+		catchUp:                        ctx.catchUp,
+		triggered:                      ctx.triggered,
+		measures:                       ctx.measures,
 		log:                            ctx.log,
 		context:                        childContext,
 		contextCancelFunc:              childContextCancelFunc,
@@ -162,7 +203,9 @@ func (ctx *ContextImpl) LogWarn(msg string, fields ...LogFields) {
 // AddSingleMetadata método adiciona 1 metadata no contexto.
 func (ctx *ContextImpl) AddSingleMetadata(key string, args interface{}) Context {
 	copyCtx := ctx.copy()
+	copyCtx.measures.mu.Lock()
 	copyCtx.metadata.Set(key, args)
+	copyCtx.measures.mu.Unlock()
 	copyCtx.log = copyCtx.log.AddField(key, args)
 
 	return copyCtx
@@ -172,40 +215,73 @@ func (ctx *ContextImpl) AddSingleMetadata(key string, args interface{}) Context
 func (ctx *ContextImpl) AddMetadata(metadata Metadata) Context {
 	copyCtx := ctx.copy()
 
+	copyCtx.measures.mu.Lock()
 	for key, value := range metadata {
 		copyCtx.metadata.Set(key, value)
 		copyCtx.log = copyCtx.log.AddField(key, value)
 	}
+	copyCtx.measures.mu.Unlock()
 
 	return copyCtx
 }
 
-func (ctx *ContextImpl) metrics(watch *Watch, now time.Time) {
-	watch.outis.Event(ctx, EventMetric{
-		ID:         ctx.id.ToString(),
-		StartedAt:  now,
-		FinishedAt: time.Now(),
-		Latency:    time.Since(now),
-		Metadata:   ctx.metadata,
-		Indicators: ctx.indicator,
-		Histograms: ctx.histogram,
-		Watcher: WatcherMetric{
-			ID:    watch.Id.ToString(),
-			Name:  watch.Name,
-			RunAt: watch.RunAt,
-		},
-		Routine: RoutineMetric{
-			ID:        ctx.routineID.ToString(),
-			Name:      ctx.name,
-			Path:      ctx.Path,
-			StartedAt: ctx.RunAt,
-		},
-	})
-
-	ctx.metadata, ctx.indicator, ctx.histogram = Metadata{}, []*Indicator{}, []*Histogram{}
+func (ctx *ContextImpl) metrics(watch *Watch, now time.Time, err error) {
+	var (
+		errMsg     string
+		timeoutErr *TimeoutError
+	)
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	metadata, indicators, histograms := ctx.measures.snapshot(ctx.metadata)
+
+	event := EventMetric{
+		ID:          ctx.id.ToString(),
+		Attempt:     ctx.attempt,
+		Error:       errMsg,
+		Timeout:     errors.As(err, &timeoutErr),
+		Overlap:     ctx.overlapMetric,
+		ScheduledAt: ctx.scheduledAt,
+		CatchUp:     ctx.catchUp,
+		Triggered:   ctx.triggered,
+		StartedAt:   now,
+		FinishedAt:  watch.now(),
+		Latency:     watch.since(now),
+		Metadata:    metadata,
+		Indicators:  indicators,
+		Histograms:  histograms,
+		Watcher:     watch.metric(),
+		Routine:     ctx.routineMetric(),
+	}
+
+	ctx.record(event)
+	watch.outis.Event(ctx, event)
+
+	ctx.metadata, ctx.measures = Metadata{}, newMeasures()
+}
+
+// routineMetric returns the routine data used in metrics and events
+func (ctx *ContextImpl) routineMetric() RoutineMetric {
+	return RoutineMetric{
+		ID:        ctx.routineID.ToString(),
+		Name:      ctx.name,
+		Path:      ctx.Path,
+		StartedAt: ctx.RunAt,
+	}
 }
 
 func (ctx *ContextImpl) sleep(now time.Time) {
+	now = now.In(ctx.Location)
+
+	if next := ctx.nextDay(now); !next.IsZero() {
+		ctx.LogInfo("Waiting until " + next.Format("02/01/2006 15:04:05"))
+		if !ctx.wait(next.Sub(now)) {
+			return
+		}
+		now = ctx.Watcher.now().In(ctx.Location)
+	}
+
 	startHour := now.Hour()
 	var nextTime time.Time
 
@@ -218,8 +294,10 @@ func (ctx *ContextImpl) sleep(now time.Time) {
 			sleepTime = nextTime.Sub(now)
 		}
 		ctx.LogDebug("Waiting debug hour", LogFields{"now": now.String(), "hour": now.Hour(), "start_hour": ctx.period.startHour, "next_time": nextTime.String(), "sleep_time": sleepTime.String()})
-		time.Sleep(sleepTime)
-		now = time.Now()
+		if !ctx.wait(sleepTime) {
+			return
+		}
+		now = ctx.Watcher.now().In(ctx.Location)
 	}
 
 	if ctx.period.minuteSet {
@@ -230,10 +308,75 @@ func (ctx *ContextImpl) sleep(now time.Time) {
 			sleepTime = nextTime.Sub(now)
 		}
 		ctx.LogDebug("Waiting debug minute", LogFields{"now": now.String(), "minute": now.Minute(), "start_hour": startHour, "start_minute": ctx.period.startMinute, "next_time": nextTime.String(), "sleep_time": sleepTime.String()})
-		time.Sleep(sleepTime)
+		ctx.wait(sleepTime)
+	}
+}
+
+// wait blocks for the given duration, returning false when the context is done before
+func (ctx *ContextImpl) wait(duration time.Duration) bool {
+	if duration <= 0 {
+		return ctx.context.Err() == nil
+	}
+
+	timer := ctx.Watcher.newTimer(duration)
+	defer timer.Stop()
+
+	ctx.Watcher.waiting()
+	select {
+	case <-ctx.context.Done():
+		ctx.Watcher.running()
+		return false
+	case <-timer.C():
+		return true
 	}
 }
 
+// dayAllowed returns whether the routine can be executed in the day of the time, according
+// to the days of the week, the days of the month, the months and the holidays of the routine
+func (ctx *ContextImpl) dayAllowed(t time.Time) bool {
+	t = t.In(ctx.Location)
+
+	if ctx.period.holidays != nil {
+		if _, holiday := ctx.period.holidays.Holiday(t); holiday {
+			return false
+		}
+	}
+
+	return containsOrEmpty(ctx.period.weekdays, t.Weekday()) &&
+		containsOrEmpty(ctx.period.monthDays, t.Day()) &&
+		containsOrEmpty(ctx.period.months, t.Month())
+}
+
+// nextDay returns the start of the next day in which the routine can be executed, when the routine can not be
+// executed in the day of the time. It returns the zero time when the day is allowed or no day is found in 5 years
+func (ctx *ContextImpl) nextDay(now time.Time) time.Time {
+	if ctx.dayAllowed(now) {
+		return time.Time{}
+	}
+
+	for day := 1; day <= 5*366; day++ {
+		if next := wallTime(now.Year(), now.Month(), now.Day()+day, 0, 0, 0, now.Location()); ctx.dayAllowed(next) {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+func containsOrEmpty[T comparable](values []T, value T) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ctx *ContextImpl) mustWait(time int, start, end uint) bool {
 	if start <= end {
 		return !(time >= int(start) && time <= int(end))
@@ -243,11 +386,46 @@ func (ctx *ContextImpl) mustWait(time int, start, end uint) bool {
 }
 
 func (ctx *ContextImpl) nextTime(now time.Time, hour, minute int) time.Time {
-	today := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	today := wallTime(now.Year(), now.Month(), now.Day(), hour, minute, 0, now.Location())
 	if now.Before(today) {
 		return today
 	}
-	return today.Add(24 * time.Hour)
+	return wallTime(now.Year(), now.Month(), now.Day()+1, hour, minute, 0, now.Location())
+}
+
+// wallTime returns the time of the wall clock in the given location. A wall clock
+// skipped by a DST transition is moved forward by the length of the transition,
+// so it never results in a time before the transition, and a wall clock repeated
+// by a DST transition results in its first occurrence
+func wallTime(year int, month time.Month, day, hour, minute, sec int, loc *time.Location) time.Time {
+	var (
+		wall = time.Date(year, month, day, hour, minute, sec, 0, time.UTC)
+		t    = time.Date(year, month, day, hour, minute, sec, 0, loc)
+	)
+
+	if sameWallClock(t, wall) {
+		// O time.Date não garante qual das ocorrências de um horário repetido é retornada
+		for _, around := range []time.Time{t.Add(-12 * time.Hour), t.Add(12 * time.Hour)} {
+			_, offset := around.Zone()
+			if earlier := wall.Add(-time.Duration(offset) * time.Second).In(loc); earlier.Before(t) && sameWallClock(earlier, wall) {
+				t = earlier
+			}
+		}
+		return t
+	}
+
+	_, offset := t.Zone()
+	if shifted := wall.Add(-time.Duration(offset) * time.Second).In(loc); shifted.After(t) {
+		return shifted
+	}
+
+	return t
+}
+
+// sameWallClock returns whether the times have the same date and wall clock, ignoring the location
+func sameWallClock(t, wall time.Time) bool {
+	return t.Year() == wall.Year() && t.YearDay() == wall.YearDay() &&
+		t.Hour() == wall.Hour() && t.Minute() == wall.Minute() && t.Second() == wall.Second()
 }
 
 func (ctx *ContextImpl) validate() error {
@@ -263,9 +441,93 @@ func (ctx *ContextImpl) validate() error {
 		return errors.New("the routine is required")
 	}
 
+	if ctx.retryPolicy != nil {
+		if err := ctx.retryPolicy.validate(); err != nil {
+			return err
+		}
+	}
+
+	if ctx.restartPolicy != nil {
+		if err := ctx.restartPolicy.validate(); err != nil {
+			return err
+		}
+	}
+
+	if ctx.catchUpPolicy != nil {
+		if ctx.Watcher.history == nil {
+			return errors.New("the catch-up policy requires the execution history of the watcher")
+		}
+
+		if err := ctx.catchUpPolicy.validate(); err != nil {
+			return err
+		}
+	}
+
+	if ctx.jitter != nil {
+		if err := ctx.jitter.validate(); err != nil {
+			return err
+		}
+	}
+
+	if ctx.startOffset < 0 {
+		return errors.New("the start offset must not be negative")
+	}
+
+	if ctx.overlap.mode == "" {
+		ctx.overlap = OverlapDelay()
+	}
+
+	if err := ctx.overlap.validate(); err != nil {
+		return err
+	}
+
+	if ctx.Location == nil {
+		ctx.Location = time.Local
+	}
+
+	for _, day := range ctx.period.monthDays {
+		if day < 1 || day > 31 {
+			return errors.New("the days of the month must be between 1 and 31")
+		}
+	}
+
+	if now := ctx.Watcher.now().In(ctx.Location); !ctx.dayAllowed(now) && ctx.nextDay(now).IsZero() {
+		return errors.New("the day restrictions of the routine never allow an execution")
+	}
+
+	if ctx.Cron != "" {
+		schedule, err := parseCron(ctx.Cron)
+		if err != nil {
+			return err
+		}
+
+		if schedule.Next(ctx.Watcher.now()).IsZero() {
+			return fmt.Errorf("the cron expression '%s' is never satisfied", ctx.Cron)
+		}
+
+		ctx.schedule = schedule
+	}
+
 	return nil
 }
 
+// newExecution creates the context of a single execution of the routine
+func (ctx *ContextImpl) newExecution() *ContextImpl {
+	exec := ctx.copy()
+	exec.metadata, exec.measures = make(Metadata), newMeasures()
+	return exec
+}
+
+// next returns the next execution time of the routine, based on
+// the cron expression or on the interval
+func (ctx *ContextImpl) next(now time.Time) time.Time {
+	if ctx.schedule != nil {
+		return ctx.schedule.Next(now.In(ctx.Location))
+	}
+
+	return now.Add(ctx.Interval)
+}
+
 // Name returns the name of the routine
 func (ctx *ContextImpl) Name() string {
 	return ctx.name
@@ -280,3 +542,23 @@ func (ctx *ContextImpl) RoutineID() ID {
 func (ctx *ContextImpl) ID() ID {
 	return ctx.id
 }
+
+// Attempt returns the number of the execution attempt, starting at 1
+func (ctx *ContextImpl) Attempt() int {
+	return ctx.attempt
+}
+
+// ScheduledAt returns the logical time of the execution, which is the time the execution was
+// scheduled for, even when it is started later because of the period or of the overlap policy
+func (ctx *ContextImpl) ScheduledAt() time.Time {
+	return ctx.scheduledAt
+}
+
+// PreviousScheduledAt returns the logical time of the previous execution of the schedule of the routine,
+// whatever its outcome, including the catch-ups. The triggered executions are out of the schedule, they
+// receive the time of the previous scheduled execution but are not the previous execution of the next ones.
+// With the execution history, the time is loaded on startup from the last execution of the schedule
+// recorded, so it is the same after a restart. It returns the zero time on the first execution
+func (ctx *ContextImpl) PreviousScheduledAt() time.Time {
+	return ctx.previousScheduledAt
+}
@@ -0,0 +1,136 @@
+package outis
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestWallTime(t *testing.T) {
+	var (
+		berlin    = loadLocation(t, "Europe/Berlin")
+		saoPaulo  = loadLocation(t, "America/Sao_Paulo")
+		utcOffset = func(hours int) *time.Location { return time.FixedZone("", hours*60*60) }
+	)
+
+	for _, test := range []struct {
+		name                   string
+		year                   int
+		month                  time.Month
+		day, hour, minute, sec int
+		loc                    *time.Location
+		expected               time.Time
+	}{
+		{
+			name: "regular day", year: 2024, month: time.March, day: 30, hour: 2, minute: 30, loc: berlin,
+			expected: time.Date(2024, time.March, 30, 2, 30, 0, 0, utcOffset(1)),
+		},
+		{
+			name: "spring forward moves the skipped hour forward", year: 2024, month: time.March, day: 31, hour: 2, minute: 30, loc: berlin,
+			expected: time.Date(2024, time.March, 31, 3, 30, 0, 0, utcOffset(2)),
+		},
+		{
+			name: "spring forward keeps the hour after the transition", year: 2024, month: time.March, day: 31, hour: 3, loc: berlin,
+			expected: time.Date(2024, time.March, 31, 3, 0, 0, 0, utcOffset(2)),
+		},
+		{
+			name: "spring forward at midnight", year: 2018, month: time.November, day: 4, loc: saoPaulo,
+			expected: time.Date(2018, time.November, 4, 1, 0, 0, 0, utcOffset(-2)),
+		},
+		{
+			name: "fall back uses the first occurrence", year: 2024, month: time.October, day: 27, hour: 2, minute: 30, loc: berlin,
+			expected: time.Date(2024, time.October, 27, 2, 30, 0, 0, utcOffset(2)),
+		},
+		{
+			name: "fall back at midnight uses the first occurrence", year: 2019, month: time.February, day: 16, hour: 23, minute: 30, loc: saoPaulo,
+			expected: time.Date(2019, time.February, 16, 23, 30, 0, 0, utcOffset(-2)),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			actual := wallTime(test.year, test.month, test.day, test.hour, test.minute, test.sec, test.loc)
+			assert.True(t, test.expected.Equal(actual), "expected %s, found %s", test.expected, actual)
+		})
+	}
+}
+
+func TestNextTimeDST(t *testing.T) {
+	var (
+		ctx    = &ContextImpl{}
+		berlin = loadLocation(t, "Europe/Berlin")
+	)
+
+	// No dia em que o horário de verão começa, o horário pulado é executado logo após a transição
+	now := time.Date(2024, time.March, 31, 1, 0, 0, 0, berlin)
+	next := ctx.nextTime(now, 2, 30)
+	assert.Equal(t, time.Date(2024, time.March, 31, 1, 30, 0, 0, time.UTC), next.UTC())
+	assert.Equal(t, time.Date(2024, time.April, 1, 2, 30, 0, 0, berlin), ctx.nextTime(next, 2, 30))
+
+	// No dia em que o horário de verão termina, o horário repetido é executado uma única vez
+	now = time.Date(2024, time.October, 27, 1, 0, 0, 0, berlin)
+	first := ctx.nextTime(now, 2, 30)
+	assert.Equal(t, time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC), first.UTC())
+
+	repeated := first.Add(time.Hour)
+	require.Equal(t, 2, repeated.Hour())
+	require.Equal(t, 30, repeated.Minute())
+	for _, from := range []time.Time{first, repeated} {
+		assert.Equal(t, time.Date(2024, time.October, 28, 2, 30, 0, 0, berlin), ctx.nextTime(from, 2, 30))
+	}
+}
+
+func TestNextDayDST(t *testing.T) {
+	saoPaulo := loadLocation(t, "America/Sao_Paulo")
+	ctx := &ContextImpl{period: period{monthDays: []int{4}}, Location: saoPaulo}
+
+	// A meia-noite de 04/11/2018 não existiu em São Paulo, o dia começou à 01:00
+	next := ctx.nextDay(time.Date(2018, time.November, 3, 12, 0, 0, 0, saoPaulo))
+	assert.Equal(t, time.Date(2018, time.November, 4, 1, 0, 0, 0, saoPaulo), next)
+	assert.Equal(t, 4, next.Day())
+}
+
+func TestCronNextDST(t *testing.T) {
+	berlin := loadLocation(t, "Europe/Berlin")
+
+	schedule, err := parseCron("30 2 * * *")
+	require.NoError(t, err)
+
+	// O horário pulado é executado logo após a transição
+	next := schedule.Next(time.Date(2024, time.March, 31, 1, 0, 0, 0, berlin))
+	assert.Equal(t, time.Date(2024, time.March, 31, 1, 30, 0, 0, time.UTC), next.UTC())
+	assert.Equal(t, time.Date(2024, time.April, 1, 2, 30, 0, 0, berlin), schedule.Next(next))
+
+	// O horário repetido é executado uma única vez
+	first := schedule.Next(time.Date(2024, time.October, 27, 1, 0, 0, 0, berlin))
+	assert.Equal(t, time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC), first.UTC())
+	assert.Equal(t, time.Date(2024, time.October, 28, 2, 30, 0, 0, berlin), schedule.Next(first))
+	assert.Equal(t, time.Date(2024, time.October, 28, 2, 30, 0, 0, berlin), schedule.Next(first.Add(30*time.Minute)))
+
+	// Uma expressão a cada 30 minutos executa cada instante uma única vez durante a repetição
+	schedule, err = parseCron("*/30 * * * *")
+	require.NoError(t, err)
+
+	var (
+		runs []time.Time
+		from = time.Date(2024, time.October, 27, 1, 45, 0, 0, berlin)
+	)
+	for next := schedule.Next(from); len(runs) < 4; next = schedule.Next(next) {
+		runs = append(runs, next.UTC())
+	}
+	assert.Equal(t, []time.Time{
+		time.Date(2024, time.October, 27, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC),
+		time.Date(2024, time.October, 27, 2, 0, 0, 0, time.UTC),
+		time.Date(2024, time.October, 27, 2, 30, 0, 0, time.UTC),
+	}, runs)
+}
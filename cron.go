@@ -0,0 +1,221 @@
+package outis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros defines the predefined cron expressions
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]uint{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+// cronSchedule defines a parsed cron expression, each field is
+// represented by a bit set of the allowed values
+type cronSchedule struct {
+	expr                             string
+	second, minute, hour, dom, month uint64
+	dow                              uint64
+	domStar, dowStar                 bool
+}
+
+// parseCron parses a cron expression with 5 fields (minute, hour, day of month,
+// month and day of week), 6 fields (with seconds at the beginning) or a macro
+func parseCron(expr string) (*cronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression '%s': expected 5 or 6 fields, found %d", expr, len(fields))
+	}
+
+	var (
+		schedule = &cronSchedule{expr: expr}
+		err      error
+	)
+
+	for _, item := range []struct {
+		value string
+		field cronField
+		bits  *uint64
+	}{
+		{fields[0], cronSecond, &schedule.second},
+		{fields[1], cronMinute, &schedule.minute},
+		{fields[2], cronHour, &schedule.hour},
+		{fields[3], cronDom, &schedule.dom},
+		{fields[4], cronMonth, &schedule.month},
+		{fields[5], cronDow, &schedule.dow},
+	} {
+		if *item.bits, err = item.field.parse(item.value); err != nil {
+			return nil, fmt.Errorf("invalid cron expression '%s': %w", expr, err)
+		}
+	}
+
+	// Domingo pode ser informado como 0 ou 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	schedule.domStar = fields[3] == "*" || fields[3] == "?"
+	schedule.dowStar = fields[5] == "*" || fields[5] == "?"
+
+	return schedule, nil
+}
+
+// parse converts a field of the cron expression into a bit set
+func (f cronField) parse(value string) (bits uint64, err error) {
+	for _, part := range strings.Split(value, ",") {
+		var (
+			start, end      = f.min, f.max
+			step       uint = 1
+		)
+
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			if step, err = f.number(stepExpr, false); err != nil {
+				return 0, err
+			}
+			if step == 0 {
+				return 0, fmt.Errorf("invalid step '%s' in %s field", part, f.name)
+			}
+		}
+
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+		case strings.Contains(rangeExpr, "-"):
+			startExpr, endExpr, _ := strings.Cut(rangeExpr, "-")
+			if start, err = f.number(startExpr, true); err != nil {
+				return 0, err
+			}
+			if end, err = f.number(endExpr, true); err != nil {
+				return 0, err
+			}
+		default:
+			if start, err = f.number(rangeExpr, true); err != nil {
+				return 0, err
+			}
+			if !hasStep {
+				end = start
+			}
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("invalid range '%s' in %s field", part, f.name)
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+
+	return bits, nil
+}
+
+// number converts a value of the field, accepting names when allowed
+func (f cronField) number(value string, checkBounds bool) (uint, error) {
+	if number, ok := f.names[strings.ToUpper(value)]; ok {
+		return number, nil
+	}
+
+	number, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' in %s field", value, f.name)
+	}
+
+	if checkBounds && (uint(number) < f.min || uint(number) > f.max) {
+		return 0, fmt.Errorf("value '%s' out of range [%d-%d] in %s field", value, f.min, f.max, f.name)
+	}
+
+	return uint(number), nil
+}
+
+// Next returns the next time after the given time that satisfies the expression.
+//
+// The search is done over the wall clock of the location of the given time, so
+// a time skipped by a DST transition fires once right after the transition and
+// a wall clock that repeats itself does not fire twice.
+// A zero time is returned when the expression is not satisfied in five years.
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	var (
+		loc   = after.Location()
+		wall  = time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), after.Second(), 0, time.UTC).Add(time.Second)
+		limit = wall.AddDate(5, 0, 0)
+	)
+
+	for wall.Before(limit) {
+		switch {
+		case s.month&(1<<uint(wall.Month())) == 0:
+			wall = time.Date(wall.Year(), wall.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(wall):
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(wall.Hour())) == 0:
+			wall = wall.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(wall.Minute())) == 0:
+			wall = wall.Truncate(time.Minute).Add(time.Minute)
+		case s.second&(1<<uint(wall.Second())) == 0:
+			wall = wall.Add(time.Second)
+		default:
+			next := wallTime(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), loc)
+			if next.After(after) {
+				return next
+			}
+			// O horário já foi executado antes da repetição do relógio (DST)
+			wall = wall.Add(time.Second)
+		}
+	}
+
+	return time.Time{}
+}
+
+// dayMatches checks the day of month and day of week fields, when both
+// are restricted it is enough that one of them matches
+func (s *cronSchedule) dayMatches(wall time.Time) bool {
+	domMatch := s.dom&(1<<uint(wall.Day())) != 0
+	dowMatch := s.dow&(1<<uint(wall.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// String returns the cron expression
+func (s *cronSchedule) String() string {
+	return s.expr
+}
@@ -0,0 +1,157 @@
+package outis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bits returns the bit set of the values
+func bits(values ...uint) (set uint64) {
+	for _, value := range values {
+		set |= 1 << value
+	}
+	return set
+}
+
+func TestCronFieldParse(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		field cronField
+		value string
+		bits  uint64
+	}{
+		{name: "wildcard", field: cronHour, value: "*", bits: bits(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23)},
+		{name: "question mark", field: cronDom, value: "?", bits: bits(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31)},
+		{name: "single value", field: cronMinute, value: "30", bits: bits(30)},
+		{name: "lower bound", field: cronSecond, value: "0", bits: bits(0)},
+		{name: "upper bound", field: cronSecond, value: "59", bits: bits(59)},
+		{name: "range", field: cronHour, value: "8-11", bits: bits(8, 9, 10, 11)},
+		{name: "list", field: cronMinute, value: "0,15,45", bits: bits(0, 15, 45)},
+		{name: "list of ranges", field: cronHour, value: "1-2,22-23", bits: bits(1, 2, 22, 23)},
+		{name: "wildcard step", field: cronMinute, value: "*/15", bits: bits(0, 15, 30, 45)},
+		{name: "range step", field: cronHour, value: "8-18/5", bits: bits(8, 13, 18)},
+		{name: "start step", field: cronDom, value: "25/3", bits: bits(25, 28, 31)},
+		{name: "month names", field: cronMonth, value: "JAN,jun-Aug", bits: bits(1, 6, 7, 8)},
+		{name: "day names", field: cronDow, value: "MON-FRI", bits: bits(1, 2, 3, 4, 5)},
+		{name: "sunday as seven", field: cronDow, value: "7", bits: bits(7)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := test.field.parse(test.value)
+			require.NoError(t, err)
+			assert.Equal(t, test.bits, actual)
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, test := range []struct {
+		expr, err string
+	}{
+		{expr: "", err: "expected 5 or 6 fields, found 0"},
+		{expr: "* * * *", err: "expected 5 or 6 fields, found 4"},
+		{expr: "* * * * * * *", err: "expected 5 or 6 fields, found 7"},
+		{expr: "@every", err: "expected 5 or 6 fields, found 1"},
+		{expr: "60 * * * *", err: "value '60' out of range [0-59] in minute field"},
+		{expr: "60 * * * * *", err: "value '60' out of range [0-59] in second field"},
+		{expr: "* 24 * * *", err: "value '24' out of range [0-23] in hour field"},
+		{expr: "* * 0 * *", err: "value '0' out of range [1-31] in day of month field"},
+		{expr: "* * 32 * *", err: "value '32' out of range [1-31] in day of month field"},
+		{expr: "* * * 13 *", err: "value '13' out of range [1-12] in month field"},
+		{expr: "* * * * 8", err: "value '8' out of range [0-7] in day of week field"},
+		{expr: "*/0 * * * *", err: "invalid step '*/0' in minute field"},
+		{expr: "*/x * * * *", err: "invalid value 'x' in minute field"},
+		{expr: "5-1 * * * *", err: "invalid range '5-1' in minute field"},
+		{expr: "1- * * * *", err: "invalid value '' in minute field"},
+		{expr: "1,,2 * * * *", err: "invalid value '' in minute field"},
+		{expr: "-1 * * * *", err: "invalid value '' in minute field"},
+		{expr: "* * * FOO *", err: "invalid value 'FOO' in month field"},
+		{expr: "* * * * MON-FOO", err: "invalid value 'FOO' in day of week field"},
+	} {
+		t.Run(test.expr, func(t *testing.T) {
+			_, err := parseCron(test.expr)
+			assert.EqualError(t, err, "invalid cron expression '"+test.expr+"': "+test.err)
+		})
+	}
+}
+
+func TestParseCronMacros(t *testing.T) {
+	for macro, expr := range cronMacros {
+		fromMacro, err := parseCron(macro)
+		require.NoError(t, err)
+
+		fromExpr, err := parseCron(expr)
+		require.NoError(t, err)
+
+		fromMacro.expr, fromExpr.expr = "", ""
+		assert.Equal(t, fromExpr, fromMacro, macro)
+	}
+
+	schedule, err := parseCron(" @Daily ")
+	require.NoError(t, err)
+	assert.Equal(t, " @Daily ", schedule.String())
+}
+
+func TestCronNext(t *testing.T) {
+	// 2024-01-10 é uma quarta-feira
+	from := time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name, expr string
+		from       time.Time
+		next       time.Time
+	}{
+		{name: "every minute", expr: "* * * * *", next: time.Date(2024, 1, 10, 10, 31, 0, 0, time.UTC)},
+		{name: "every second", expr: "* * * * * *", next: time.Date(2024, 1, 10, 10, 30, 1, 0, time.UTC)},
+		{name: "strictly after", expr: "30 10 * * *", next: time.Date(2024, 1, 11, 10, 30, 0, 0, time.UTC)},
+		{name: "ignores the fraction of second", expr: "* * * * * *", from: from.Add(500 * time.Millisecond), next: time.Date(2024, 1, 10, 10, 30, 1, 0, time.UTC)},
+		{name: "later today", expr: "0 18 * * *", next: time.Date(2024, 1, 10, 18, 0, 0, 0, time.UTC)},
+		{name: "minute step", expr: "*/20 * * * *", next: time.Date(2024, 1, 10, 10, 40, 0, 0, time.UTC)},
+		{name: "seconds field", expr: "15,45 30 10 * * *", next: time.Date(2024, 1, 10, 10, 30, 15, 0, time.UTC)},
+		{name: "hour list", expr: "0 8,12 * * *", next: time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)},
+		{name: "next month", expr: "0 0 1 * *", next: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "month name", expr: "0 0 1 MAR *", next: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "next year", expr: "0 0 1 1 *", next: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "weekday", expr: "0 9 * * MON-FRI", next: time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC)},
+		{name: "weekend", expr: "0 9 * * SAT,SUN", next: time.Date(2024, 1, 13, 9, 0, 0, 0, time.UTC)},
+		{name: "sunday as seven", expr: "0 0 * * 7", next: time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{name: "last day of short month", expr: "0 0 31 * *", next: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "skips months without the day", expr: "0 0 31 * *", from: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), next: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day", expr: "0 0 29 2 *", next: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day years later", expr: "0 0 29 2 *", from: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), next: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or day of week", expr: "0 0 15 * FRI", next: time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or day of week, month day first", expr: "0 0 11 * MON", next: time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "day of week restricted by day of month wildcard", expr: "0 0 ? * MON", next: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month restricted by day of week wildcard", expr: "0 0 20 * ?", next: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{name: "never within five years", expr: "0 0 30 2 *", next: time.Time{}},
+		{name: "april 31st", expr: "0 0 31 APR *", next: time.Time{}},
+		{name: "keeps the location", expr: "0 9 * * *", from: time.Date(2024, 1, 10, 10, 30, 0, 0, time.FixedZone("BRT", -3*60*60)), next: time.Date(2024, 1, 11, 9, 0, 0, 0, time.FixedZone("BRT", -3*60*60))},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := parseCron(test.expr)
+			require.NoError(t, err)
+
+			after := test.from
+			if after.IsZero() {
+				after = from
+			}
+
+			next := schedule.Next(after)
+			assert.True(t, test.next.Equal(next), "expected %s, found %s", test.next, next)
+			if !test.next.IsZero() {
+				assert.Equal(t, test.next.Location().String(), next.Location().String())
+			}
+		})
+	}
+}
+
+func TestCronNextSearchLimit(t *testing.T) {
+	schedule, err := parseCron("0 0 29 2 *")
+	require.NoError(t, err)
+
+	// O próximo 29 de fevereiro após 2096 é em 2104, mais de cinco anos depois
+	assert.True(t, schedule.Next(time.Date(2096, 3, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+	assert.Equal(t, time.Date(2096, 2, 29, 0, 0, 0, 0, time.UTC), schedule.Next(time.Date(2092, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -1,11 +1,25 @@
 package outis
 
 import (
+	"context"
 	"fmt"
+	"runtime/debug"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrRoutineNotFound is returned when the watcher has no routine with the identifier
+	ErrRoutineNotFound = errors.New("routine not found")
+	// ErrTriggerPending is returned when a trigger of the routine is still waiting to be executed
+	ErrTriggerPending = errors.New("a trigger of the routine is already pending")
+	// ErrDuplicateRoutine is returned when the watcher already has a running routine with the identifier
+	ErrDuplicateRoutine = errors.New("a routine with the same id is already running")
+	// errWatcherClosed is returned when a routine is created after the shutdown of the watcher
+	errWatcherClosed = errors.New("the watcher is shut down")
+)
+
 type stackTracer interface {
 	StackTrace() errors.StackTrace
 }
@@ -31,3 +45,55 @@ func reconstructStackTrace(err error) (output []string, traced bool) {
 	}
 	return
 }
+
+// TimeoutError defines the error of a script execution that exceeded the timeout
+type TimeoutError struct {
+	RoutineID ID
+	ID        ID
+	Timeout   time.Duration
+}
+
+// Error returns the error message
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("the execution '%s' of the routine '%s' exceeded the timeout of %s", e.ID, e.RoutineID, e.Timeout)
+}
+
+// Unwrap returns the error of the context deadline
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// PanicError defines the error of a panic recovered in a routine, the routine is not
+// interrupted by the panic and continues with its next execution
+type PanicError struct {
+	RoutineID ID
+	// ID is the identifier of the execution, empty when the panic happened in the loop of the routine
+	ID ID
+	// Value is the value passed to panic
+	Value interface{}
+	// Stack is the stack trace of the goroutine that panicked
+	Stack []byte
+}
+
+// newPanicError creates the error of the recovered panic, it must be called by the deferred function that recovered it
+func newPanicError(routineID, id ID, value interface{}) *PanicError {
+	return &PanicError{RoutineID: routineID, ID: id, Value: value, Stack: debug.Stack()}
+}
+
+// Error returns the error message
+func (e *PanicError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("the routine '%s' panicked: %v", e.RoutineID, e.Value)
+	}
+
+	return fmt.Sprintf("the execution '%s' of the routine '%s' panicked: %v", e.ID, e.RoutineID, e.Value)
+}
+
+// Unwrap returns the value of the panic when it is an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
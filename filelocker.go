@@ -0,0 +1,166 @@
+package outis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// fileLockerStaleGuard is the age of a guard file considered abandoned by a finished process
+	fileLockerStaleGuard = 30 * time.Second
+	// fileLockerRetry is the interval between attempts to acquire the guard file
+	fileLockerRetry = 10 * time.Millisecond
+)
+
+var fileLockerInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// fileLocker implements a Locker using lease files in a directory shared between the processes
+type fileLocker struct {
+	dir string
+}
+
+type fileLease struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewFileLocker creates a Locker that keeps the leases in files of the directory,
+// the directory must be shared by every process that executes the routines
+func NewFileLocker(dir string) (Locker, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &fileLocker{dir: dir}, nil
+}
+
+// Lock tries to acquire the lock of the key for the owner during the ttl
+func (l *fileLocker) Lock(ctx context.Context, key, owner string, ttl time.Duration) (acquired bool, err error) {
+	err = l.guard(ctx, key, func() error {
+		lease, err := l.read(key)
+		if err != nil {
+			return err
+		}
+
+		if lease != nil && lease.Owner != owner && time.Now().Before(lease.ExpiresAt) {
+			return nil
+		}
+
+		acquired = true
+		return l.write(key, fileLease{Owner: owner, ExpiresAt: time.Now().Add(ttl)})
+	})
+
+	return acquired && err == nil, err
+}
+
+// Refresh extends the lease of a lock held by the owner
+func (l *fileLocker) Refresh(ctx context.Context, key, owner string, ttl time.Duration) (renewed bool, err error) {
+	err = l.guard(ctx, key, func() error {
+		lease, err := l.read(key)
+		if err != nil || lease == nil || lease.Owner != owner {
+			return err
+		}
+
+		renewed = true
+		return l.write(key, fileLease{Owner: owner, ExpiresAt: time.Now().Add(ttl)})
+	})
+
+	return renewed && err == nil, err
+}
+
+// Unlock releases the lock held by the owner
+func (l *fileLocker) Unlock(ctx context.Context, key, owner string) error {
+	return l.guard(ctx, key, func() error {
+		lease, err := l.read(key)
+		if err != nil || lease == nil || lease.Owner != owner {
+			return err
+		}
+
+		return os.Remove(l.path(key))
+	})
+}
+
+// guard executes the function holding the guard file of the key, which
+// ensures that a single process reads and writes the lease at a time
+func (l *fileLocker) guard(ctx context.Context, key string, fn func() error) error {
+	guard := l.path(key) + ".guard"
+
+	for {
+		file, err := os.OpenFile(guard, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			file.Close()
+			break
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+
+		if info, err := os.Stat(guard); err == nil && time.Since(info.ModTime()) > fileLockerStaleGuard {
+			os.Remove(guard)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(fileLockerRetry):
+		}
+	}
+
+	defer os.Remove(guard)
+	return fn()
+}
+
+func (l *fileLocker) read(key string) (*fileLease, error) {
+	content, err := os.ReadFile(l.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lease fileLease
+	if err = json.Unmarshal(content, &lease); err != nil {
+		return nil, fmt.Errorf("invalid lease file of the lock '%s': %w", key, err)
+	}
+
+	return &lease, nil
+}
+
+func (l *fileLocker) write(key string, lease fileLease) error {
+	content, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+
+	tmp := l.path(key) + ".tmp"
+	if err = os.WriteFile(tmp, content, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, l.path(key))
+}
+
+// path returns the lease file of the key. The characters not allowed in file names are escaped
+// byte by byte as '%' followed by the hexadecimal value, and '%' is not an allowed character,
+// so distinct keys never share a file
+func (l *fileLocker) path(key string) string {
+	name := fileLockerInvalidChars.ReplaceAllStringFunc(key, func(char string) string {
+		var escaped strings.Builder
+		for i := 0; i < len(char); i++ {
+			fmt.Fprintf(&escaped, "%%%02X", char[i])
+		}
+		return escaped.String()
+	})
+
+	return filepath.Join(l.dir, name+".lock")
+}
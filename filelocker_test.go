@@ -0,0 +1,32 @@
+package outis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLocker(t *testing.T) {
+	testLocker(t, func(t *testing.T) Locker {
+		locker, err := NewFileLocker(t.TempDir())
+		require.NoError(t, err)
+		return locker
+	})
+}
+
+func TestFileLockerPath(t *testing.T) {
+	locker := &fileLocker{dir: "locks"}
+
+	for _, test := range []struct {
+		key, path string
+	}{
+		{key: "routine-1.v2", path: "locks/routine-1.v2.lock"},
+		{key: "a/b", path: "locks/a%2Fb.lock"},
+		{key: "a_b", path: "locks/a_b.lock"},
+		{key: "a%2Fb", path: "locks/a%252Fb.lock"},
+		{key: "ção", path: "locks/%C3%A7%C3%A3o.lock"},
+	} {
+		assert.Equal(t, test.path, locker.path(test.key), test.key)
+	}
+}
@@ -0,0 +1,327 @@
+package outis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// fileStoreMaxLine is the maximum size of a line of the history file
+const fileStoreMaxLine = 16 * 1024 * 1024
+
+// fileStore implements an ExecutionStore using a file with one JSON execution per line.
+// The filtered fields of every execution are kept in an index in memory, so the queries
+// only read and decode the lines of the executions returned
+type fileStore struct {
+	mu    sync.Mutex
+	path  string
+	index []fileStoreEntry
+	// size is the amount of bytes of the file already indexed
+	size int64
+}
+
+// fileStoreEntry defines the position of an execution in the file and the fields used by the queries
+type fileStoreEntry struct {
+	routineID   string
+	outcome     ExecutionOutcome
+	startedAt   time.Time
+	scheduledAt time.Time
+	triggered   bool
+	offset      int64
+	length      int64
+}
+
+// NewFileStore creates an ExecutionStore that appends the executions to the file in the JSON lines format.
+// The file must be used by a single process, it is indexed when the store is created
+func NewFileStore(path string) (ExecutionStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err = file.Close(); err != nil {
+		return nil, err
+	}
+
+	store := &fileStore{path: path}
+	if err = store.sync(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Save appends the execution to the file
+func (s *fileStore) Save(ctx context.Context, execution Execution) error {
+	line, err := json.Marshal(execution)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err = s.sync(ctx); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	// Uma linha incompleta no fim do arquivo, deixada por uma gravação interrompida, é
+	// descartada para que a nova execução não seja gravada na mesma linha
+	if info, err := file.Stat(); err != nil || info.Size() > s.size {
+		if err == nil {
+			err = file.Truncate(s.size)
+		}
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	s.index = append(s.index, newFileStoreEntry(execution, s.size, int64(len(line))))
+	s.size += int64(len(line)) + 1
+
+	return file.Close()
+}
+
+// Query returns the executions that match the query, the most recent first
+func (s *fileStore) Query(ctx context.Context, query ExecutionQuery) ([]Execution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.sync(ctx); err != nil {
+		return nil, err
+	}
+
+	entries := make([]fileStoreEntry, 0)
+	for _, entry := range s.index {
+		if query.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if query.SortByScheduledAt {
+			return entries[i].scheduledAt.After(entries[j].scheduledAt)
+		}
+		return entries[i].startedAt.After(entries[j].startedAt)
+	})
+
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	executions := make([]Execution, 0, len(entries))
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		line := make([]byte, entry.length)
+		if _, err = file.ReadAt(line, entry.offset); err != nil {
+			return nil, err
+		}
+
+		var execution Execution
+		if err = json.Unmarshal(line, &execution); err != nil {
+			return nil, err
+		}
+		executions = append(executions, execution)
+	}
+
+	return executions, nil
+}
+
+// Prune rewrites the file without the executions started before the time
+func (s *fileStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.sync(ctx); err != nil {
+		return 0, err
+	}
+
+	var removed int
+	for _, entry := range s.index {
+		if entry.startedAt.Before(before) {
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(temp.Name())
+
+	var (
+		writer = bufio.NewWriter(temp)
+		index  = make([]fileStoreEntry, 0, len(s.index)-removed)
+		size   int64
+	)
+
+	// As linhas mantidas são copiadas sem serem decodificadas
+	for _, entry := range s.index {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if entry.startedAt.Before(before) {
+			continue
+		}
+
+		if _, err = io.Copy(writer, io.NewSectionReader(file, entry.offset, entry.length+1)); err != nil {
+			break
+		}
+
+		entry.offset, size = size, size+entry.length+1
+		index = append(index, entry)
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if cerr := temp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err = os.Rename(temp.Name(), s.path); err != nil {
+		return 0, err
+	}
+
+	s.index, s.size = index, size
+	return removed, nil
+}
+
+// sync indexes the lines appended to the file after the last indexing, the whole
+// file is indexed again when it is smaller than the indexed size
+func (s *fileStore) sync(ctx context.Context) error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Size() == s.size:
+		return nil
+	case info.Size() < s.size:
+		s.index, s.size = nil, 0
+	}
+
+	if _, err = file.Seek(s.size, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		line, err := readFileStoreLine(reader)
+		if errors.Is(err, io.EOF) {
+			// Uma linha incompleta no fim do arquivo é indexada quando for concluída
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		offset, length := s.size, int64(len(line))-1
+		s.size += int64(len(line))
+		if length == 0 {
+			continue
+		}
+
+		var execution Execution
+		if err = json.Unmarshal(line[:length], &execution); err != nil {
+			return err
+		}
+		s.index = append(s.index, newFileStoreEntry(execution, offset, length))
+	}
+}
+
+// readFileStoreLine reads a line of the file including the line break,
+// io.EOF is returned when the line is not complete
+func readFileStoreLine(reader *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > fileStoreMaxLine {
+			return nil, bufio.ErrTooLong
+		}
+
+		switch {
+		case err == nil:
+			return line, nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
+
+// newFileStoreEntry creates the entry of the index of the execution
+func newFileStoreEntry(execution Execution, offset, length int64) fileStoreEntry {
+	return fileStoreEntry{
+		routineID:   execution.RoutineID,
+		outcome:     execution.Outcome,
+		startedAt:   execution.StartedAt,
+		scheduledAt: execution.ScheduledAt,
+		triggered:   execution.Triggered,
+		offset:      offset,
+		length:      length,
+	}
+}
+
+// matches returns whether the indexed execution matches the filters of the query
+func (q ExecutionQuery) matches(entry fileStoreEntry) bool {
+	return (q.RoutineID == "" || entry.routineID == q.RoutineID.ToString()) &&
+		(q.Outcome == "" || entry.outcome == q.Outcome) &&
+		(!q.Scheduled || !entry.triggered && !entry.scheduledAt.IsZero()) &&
+		(q.From.IsZero() || !entry.startedAt.Before(q.From)) &&
+		(q.To.IsZero() || entry.startedAt.Before(q.To))
+}
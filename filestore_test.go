@@ -0,0 +1,103 @@
+package outis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	testExecutionStore(t, func(t *testing.T) ExecutionStore {
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "history", "executions.jsonl"))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		path      = filepath.Join(t.TempDir(), "executions.jsonl")
+		startedAt = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	)
+
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, Execution{ID: "first", RoutineID: "a", StartedAt: startedAt, Outcome: OutcomeSuccess}))
+
+	// O índice é reconstruído a partir do arquivo existente
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Save(ctx, Execution{ID: "second", RoutineID: "a", StartedAt: startedAt.Add(time.Minute)}))
+
+	executions, err := reopened.Query(ctx, ExecutionQuery{RoutineID: "a"})
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	assert.Equal(t, "second", executions[0].ID)
+	assert.Equal(t, "first", executions[1].ID)
+
+	// As linhas gravadas por outra instância são indexadas na consulta seguinte
+	executions, err = store.Query(ctx, ExecutionQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	assert.Equal(t, "second", executions[0].ID)
+}
+
+func TestFileStoreIncompleteLine(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(t.TempDir(), "executions.jsonl")
+	)
+
+	require.NoError(t, os.WriteFile(path, []byte("{\"id\":\"first\",\"routine_id\":\"a\"}\n\n{\"id\":\"partial\""), 0o644))
+
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	executions, err := store.Query(ctx, ExecutionQuery{})
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	assert.Equal(t, "first", executions[0].ID)
+}
+
+func TestFileStoreInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "executions.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o644))
+
+	_, err := NewFileStore(path)
+	assert.Error(t, err)
+}
+
+func TestFileStoreSaveAfterIncompleteLine(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		path      = filepath.Join(t.TempDir(), "executions.jsonl")
+		startedAt = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	)
+
+	// Uma gravação interrompida deixa a última linha incompleta
+	require.NoError(t, os.WriteFile(path, []byte("{\"id\":\"first\",\"routine_id\":\"a\"}\n{\"id\":\"partial\",\"rou"), 0o644))
+
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, Execution{ID: "second", RoutineID: "a", StartedAt: startedAt}))
+
+	executions, err := store.Query(ctx, ExecutionQuery{RoutineID: "a"})
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	assert.Equal(t, "second", executions[0].ID)
+
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	executions, err = reopened.Query(ctx, ExecutionQuery{})
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	assert.Equal(t, "second", executions[0].ID)
+	assert.Equal(t, "first", executions[1].ID)
+}
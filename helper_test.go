@@ -0,0 +1,60 @@
+package outis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// nopLogger discards the messages logged by the routines in the tests
+type nopLogger struct{}
+
+func (nopLogger) Level() LogLevel                        { return DebugLevel }
+func (nopLogger) Info(string, ...LogFields)              {}
+func (nopLogger) Error(error, ...LogFields)              {}
+func (nopLogger) ErrorMsg(string, ...LogFields)          {}
+func (nopLogger) Fatal(string, ...LogFields)             {}
+func (nopLogger) Panic(string, ...LogFields)             {}
+func (nopLogger) Debug(string, ...LogFields)             {}
+func (nopLogger) Warn(string, ...LogFields)              {}
+func (l nopLogger) AddFields(...LogFields) ILogger       { return l }
+func (l nopLogger) AddField(string, interface{}) ILogger { return l }
+
+// newTestWatcher creates a watcher shut down at the end of the test
+func newTestWatcher(t testing.TB, opts ...WatcherOption) *Watch {
+	watch := Watcher("watcher", "watcher", append([]WatcherOption{Logger(nopLogger{})}, opts...)...)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+	return watch
+}
+
+// exitRecorder implements IOutis sending the termination of the routines to a channel
+type exitRecorder struct {
+	IOutis
+	exits chan routineExit
+}
+
+type routineExit struct {
+	routineID ID
+	err       error
+}
+
+func newExitRecorder() *exitRecorder {
+	return &exitRecorder{IOutis: NewOutis(), exits: make(chan routineExit, 16)}
+}
+
+func (o *exitRecorder) OnRoutineExit(ctx Context, err error) {
+	o.exits <- routineExit{routineID: ctx.RoutineID(), err: err}
+}
+
+// wait returns the termination of the next routine, failing the test after the timeout
+func (o *exitRecorder) wait(t testing.TB) routineExit {
+	t.Helper()
+
+	select {
+	case exit := <-o.exits:
+		return exit
+	case <-time.After(5 * time.Second):
+		t.Fatal("the routine did not finish")
+		return routineExit{}
+	}
+}
@@ -1,10 +1,35 @@
 package outis
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
 
+// DefaultBuckets defines the default upper bounds of the histogram buckets
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// histogramRecentValues is the amount of recent values kept for GetValues
+const histogramRecentValues = 1000
+
+// Histogram defines a distribution of values, counted in buckets and summarized
+// in quantiles by a sketch, so the memory is bounded regardless of the number of values
 type Histogram struct {
-	key    string
-	values []histogramValue
+	mu      sync.Mutex
+	key     string
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+	min     float64
+	max     float64
+	sketch  *sketch
+	// recent são os últimos valores registrados, mantidos somente por compatibilidade com GetValues
+	recent []histogramValue
+	clock  IClock
 }
 
 type histogramValue struct {
@@ -12,34 +37,264 @@ type histogramValue struct {
 	createdAt time.Time
 }
 
-// NewHistogram creates a new histogram.
-func (ctx *ContextImpl) NewHistogram(key string) *Histogram {
-	histogram := &Histogram{key: key, values: make([]histogramValue, 0)}
-	ctx.histogram = append(ctx.histogram, histogram)
+// HistogramOption defines the option type of a histogram
+type HistogramOption func(*Histogram)
+
+// WithBuckets defines the upper bounds of the histogram buckets, the
+// bucket of the values greater than the last bound is always added
+func WithBuckets(buckets ...float64) HistogramOption {
+	return func(h *Histogram) { h.buckets = buckets }
+}
+
+// LinearBuckets returns count bounds, the first being start and each following one width greater
+func LinearBuckets(start, width float64, count int) []float64 {
+	buckets := make([]float64, 0, count)
+	for i := 0; i < count; i++ {
+		buckets = append(buckets, start+float64(i)*width)
+	}
+	return buckets
+}
+
+// ExponentialBuckets returns count bounds, the first being start and each following one factor times greater
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	buckets := make([]float64, 0, count)
+	for i := 0; i < count; i++ {
+		buckets = append(buckets, start*math.Pow(factor, float64(i)))
+	}
+	return buckets
+}
+
+// HistogramSnapshot defines the state of a histogram in the
+// form of cumulative buckets, as used by the metrics backends
+type HistogramSnapshot struct {
+	Key       string             `json:"key"`
+	Count     uint64             `json:"count"`
+	Sum       float64            `json:"sum"`
+	Min       float64            `json:"min"`
+	Max       float64            `json:"max"`
+	Buckets   []HistogramBucket  `json:"buckets"`
+	Quantiles map[string]float64 `json:"quantiles"`
+}
+
+// HistogramBucket defines the amount of values less than or equal to the upper bound
+type HistogramBucket struct {
+	UpperBound float64 `json:"le"`
+	Count      uint64  `json:"count"`
+}
+
+// NewHistogram creates a new histogram, or returns the histogram
+// already created with the same key in the execution.
+func (ctx *ContextImpl) NewHistogram(key string, opts ...HistogramOption) *Histogram {
+	ctx.measures.mu.Lock()
+	defer ctx.measures.mu.Unlock()
+
+	for _, histogram := range ctx.measures.histograms {
+		if histogram.key == key {
+			return histogram
+		}
+	}
+
+	histogram := newHistogram(key, ctx.Watcher.getClock(), opts...)
+	ctx.measures.histograms = append(ctx.measures.histograms, histogram)
+	return histogram
+}
+
+func newHistogram(key string, clock IClock, opts ...HistogramOption) *Histogram {
+	histogram := &Histogram{key: key, buckets: DefaultBuckets, clock: clock}
+	for _, opt := range opts {
+		opt(histogram)
+	}
+
+	histogram.buckets = append([]float64(nil), histogram.buckets...)
+	sort.Float64s(histogram.buckets)
+	histogram.counts = make([]uint64, len(histogram.buckets)+1)
+	histogram.sketch = newSketch()
+
 	return histogram
 }
 
 // GetKey get the key value of an histogram.
 func (h *Histogram) GetKey() string { return h.key }
 
-// GetValue get the values of an histogram.
+// Inc records the value 1 in the histogram.
+func (h *Histogram) Inc() { h.Add(1) }
+
+// GetValues returns the last values added to the histogram, up to 1000 values, and when they were added.
+//
+// Deprecated: the histogram no longer keeps every value, use Count, Sum, Quantile or Snapshot instead.
 func (h *Histogram) GetValues() (values []float64, times []time.Time) {
-	for _, item := range h.values {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, item := range lastRecent(h.recent) {
 		values, times = append(values, item.value), append(times, item.createdAt)
 	}
 	return
 }
 
-// Inc increments the histogram data.
-func (h *Histogram) Inc() {
-	var value float64 = 1
-	if len(h.values) != 0 {
-		value = h.values[len(h.values)-1].value + 1
+// Add add a value to the histogram. NaN and infinite values are ignored, so that
+// the sum, the minimum and the maximum are always finite and serializable
+func (h *Histogram) Add(value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
 	}
-	h.values = append(h.values, histogramValue{value: value, createdAt: time.Now()})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 || value < h.min {
+		h.min = value
+	}
+	if h.count == 0 || value > h.max {
+		h.max = value
+	}
+
+	h.counts[sort.SearchFloat64s(h.buckets, value)]++
+	h.count++
+	h.sum += value
+	h.sketch.add(value)
+	h.recent = appendRecent(h.recent, histogramValue{value: value, createdAt: h.clock.Now()})
 }
 
-// Add add a value to the histogram.
-func (h *Histogram) Add(value float64) {
-	h.values = append(h.values, histogramValue{value: value, createdAt: time.Now()})
+// appendRecent appends the values keeping the most recent ones, the slice is compacted
+// only when it doubles the limit, so the values are not copied on every addition
+func appendRecent(recent []histogramValue, values ...histogramValue) []histogramValue {
+	recent = append(recent, values...)
+	if len(recent) > 2*histogramRecentValues {
+		recent = append([]histogramValue(nil), lastRecent(recent)...)
+	}
+	return recent
+}
+
+// lastRecent returns the most recent values, up to the limit
+func lastRecent(recent []histogramValue) []histogramValue {
+	if len(recent) > histogramRecentValues {
+		return recent[len(recent)-histogramRecentValues:]
+	}
+	return recent
+}
+
+// Count returns the amount of values recorded in the histogram
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Sum returns the sum of the values recorded in the histogram
+func (h *Histogram) Sum() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sum
+}
+
+// Quantile returns the approximated value of the quantile q, clamped between 0 and 1,
+// with a relative error of 1%. It returns zero when the histogram is empty and NaN when q is NaN
+func (h *Histogram) Quantile(q float64) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.quantile(q)
+}
+
+func (h *Histogram) quantile(q float64) float64 {
+	if h.count == 0 && !math.IsNaN(q) {
+		return 0
+	}
+
+	return math.Max(h.min, math.Min(h.max, h.sketch.quantile(q)))
+}
+
+// Merge adds the values recorded in other histogram, such as the same histogram
+// of a previous execution. Both histograms must have the same buckets
+func (h *Histogram) Merge(other *Histogram) error {
+	if h == other {
+		return errors.New("a histogram cannot be merged with itself")
+	}
+
+	other = other.Clone()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.buckets) != len(other.buckets) {
+		return errors.New("the histograms have different buckets")
+	}
+	for i := range h.buckets {
+		if h.buckets[i] != other.buckets[i] {
+			return errors.New("the histograms have different buckets")
+		}
+	}
+
+	if other.count == 0 {
+		return nil
+	}
+
+	if h.count == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if h.count == 0 || other.max > h.max {
+		h.max = other.max
+	}
+
+	for i := range h.counts {
+		h.counts[i] += other.counts[i]
+	}
+	h.count += other.count
+	h.sum += other.sum
+	h.sketch.merge(other.sketch)
+	h.recent = appendRecent(h.recent, other.recent...)
+
+	return nil
+}
+
+// Snapshot returns the current state of the histogram, with the p50, p90 and p99 quantiles
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	snapshot := HistogramSnapshot{
+		Key:     h.key,
+		Count:   h.count,
+		Sum:     h.sum,
+		Min:     h.min,
+		Max:     h.max,
+		Buckets: make([]HistogramBucket, 0, len(h.buckets)),
+		Quantiles: map[string]float64{
+			"p50": h.quantile(0.5),
+			"p90": h.quantile(0.9),
+			"p99": h.quantile(0.99),
+		},
+	}
+
+	var cumulative uint64
+	for i, bound := range h.buckets {
+		cumulative += h.counts[i]
+		snapshot.Buckets = append(snapshot.Buckets, HistogramBucket{UpperBound: bound, Count: cumulative})
+	}
+
+	return snapshot
+}
+
+// MarshalJSON serializes the snapshot of the histogram
+func (h *Histogram) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.Snapshot())
+}
+
+// Clone returns a copy of the histogram that is not changed by new values
+func (h *Histogram) Clone() *Histogram {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return &Histogram{
+		key:     h.key,
+		buckets: h.buckets,
+		counts:  append([]uint64(nil), h.counts...),
+		count:   h.count,
+		sum:     h.sum,
+		min:     h.min,
+		max:     h.max,
+		sketch:  h.sketch.clone(),
+		recent:  append([]histogramValue(nil), lastRecent(h.recent)...),
+		clock:   h.clock,
+	}
 }
@@ -0,0 +1,180 @@
+package outis
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHistogram(opts ...HistogramOption) *Histogram {
+	return newHistogram("test", realClock{}, opts...)
+}
+
+// exactQuantile returns the quantile of the values with the same rank used by the sketch
+func exactQuantile(sorted []float64, q float64) float64 {
+	return sorted[int(q*float64(len(sorted)-1))]
+}
+
+func TestHistogramQuantileRelativeError(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+
+	for _, test := range []struct {
+		name     string
+		generate func() float64
+	}{
+		{name: "uniform", generate: func() float64 { return 1 + random.Float64()*10000 }},
+		{name: "exponential", generate: func() float64 { return random.ExpFloat64() * 0.25 }},
+		{name: "lognormal", generate: func() float64 { return math.Exp(random.NormFloat64() * 3) }},
+		{name: "negative", generate: func() float64 { return -1 - random.Float64()*1000 }},
+		{name: "mixed signs", generate: func() float64 { return random.NormFloat64() * 100 }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				histogram = newTestHistogram()
+				values    = make([]float64, 0, 100000)
+			)
+
+			for i := 0; i < cap(values); i++ {
+				value := test.generate()
+				values = append(values, value)
+				histogram.Add(value)
+			}
+			sort.Float64s(values)
+
+			for _, q := range []float64{0, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99, 0.999, 1} {
+				exact, approx := exactQuantile(values, q), histogram.Quantile(q)
+				assert.InDelta(t, exact, approx, math.Abs(exact)*sketchRelativeAccuracy+1e-12, "quantile %v", q)
+			}
+		})
+	}
+}
+
+func TestHistogramQuantileBounds(t *testing.T) {
+	empty := newTestHistogram()
+	assert.Zero(t, empty.Quantile(0.5))
+	assert.Zero(t, empty.Quantile(2))
+	assert.True(t, math.IsNaN(empty.Quantile(math.NaN())))
+
+	// Somente valores negativos, sem bins positivos
+	negative := newTestHistogram()
+	for _, value := range []float64{-3, -2, -1} {
+		negative.Add(value)
+	}
+
+	for _, test := range []struct {
+		q, expected float64
+	}{
+		{q: -1, expected: -3},
+		{q: 0, expected: -3},
+		{q: 1, expected: -1},
+		{q: 1.5, expected: -1},
+		{q: math.Inf(1), expected: -1},
+		{q: math.Inf(-1), expected: -3},
+	} {
+		assert.InDelta(t, test.expected, negative.Quantile(test.q), math.Abs(test.expected)*sketchRelativeAccuracy, "quantile %v", test.q)
+	}
+	assert.True(t, math.IsNaN(negative.Quantile(math.NaN())))
+
+	zeros := newTestHistogram()
+	zeros.Add(0)
+	zeros.Add(0)
+	assert.Zero(t, zeros.Quantile(1.5))
+}
+
+func TestHistogramSpecialValues(t *testing.T) {
+	histogram := newTestHistogram(WithBuckets(1, 10))
+	for _, value := range []float64{math.NaN(), 5, math.Inf(1), math.Inf(-1), 2, math.NaN()} {
+		histogram.Add(value)
+	}
+
+	assert.EqualValues(t, 2, histogram.Count(), "NaN and infinite values must be ignored")
+	assert.Equal(t, 7.0, histogram.Sum())
+	assert.InDelta(t, 2, histogram.Quantile(0), 0.02)
+	assert.InDelta(t, 5, histogram.Quantile(1), 0.05)
+
+	snapshot := histogram.Snapshot()
+	assert.Equal(t, 2.0, snapshot.Min)
+	assert.Equal(t, 5.0, snapshot.Max)
+	assert.Equal(t, []HistogramBucket{{UpperBound: 1, Count: 0}, {UpperBound: 10, Count: 2}}, snapshot.Buckets)
+}
+
+func TestHistogramJSONWithInfinity(t *testing.T) {
+	histogram := newTestHistogram(WithBuckets(1, 10))
+	for _, value := range []float64{3, math.Inf(1), math.Inf(-1), 0.5} {
+		histogram.Add(value)
+	}
+
+	data, err := json.Marshal(histogram)
+	require.NoError(t, err, "the infinite values must not break the serialization")
+
+	var snapshot HistogramSnapshot
+	require.NoError(t, json.Unmarshal(data, &snapshot))
+	assert.Equal(t, histogram.Snapshot(), snapshot)
+	assert.EqualValues(t, 2, snapshot.Count)
+	assert.Equal(t, 3.5, snapshot.Sum)
+
+	// O registro da execução no histórico também é serializável
+	_, err = json.Marshal(Execution{Histograms: []HistogramSnapshot{snapshot}})
+	assert.NoError(t, err)
+}
+
+func TestHistogramMerge(t *testing.T) {
+	var (
+		first  = newTestHistogram()
+		second = newTestHistogram()
+		values []float64
+	)
+
+	for i := 1; i <= 1000; i++ {
+		value := float64(i)
+		values = append(values, value)
+		if i%2 == 0 {
+			first.Add(value)
+		} else {
+			second.Add(value)
+		}
+	}
+	require.NoError(t, first.Merge(second))
+	assert.EqualValues(t, 1000, first.Count())
+	for _, q := range []float64{0, 0.5, 0.9, 0.99} {
+		exact := exactQuantile(values, q)
+		assert.InDelta(t, exact, first.Quantile(q), exact*sketchRelativeAccuracy, "quantile %v", q)
+	}
+
+	assert.Error(t, first.Merge(first))
+	assert.Error(t, first.Merge(newTestHistogram(WithBuckets(1, 2))))
+}
+
+func TestHistogramGetValues(t *testing.T) {
+	histogram := newTestHistogram()
+	before := time.Now()
+	histogram.Add(1)
+	histogram.Add(2)
+	histogram.Inc()
+
+	values, times := histogram.GetValues()
+	assert.Equal(t, []float64{1, 2, 1}, values)
+	require.Len(t, times, 3)
+	assert.False(t, times[0].Before(before))
+
+	for i := 0; i < 3*histogramRecentValues; i++ {
+		histogram.Add(float64(i))
+	}
+
+	values, times = histogram.GetValues()
+	require.Len(t, values, histogramRecentValues)
+	assert.Len(t, times, histogramRecentValues)
+	assert.Equal(t, float64(3*histogramRecentValues-1), values[len(values)-1])
+	assert.Equal(t, float64(2*histogramRecentValues), values[0])
+
+	clone := histogram.Clone()
+	histogram.Add(-1)
+	cloned, _ := clone.GetValues()
+	assert.Equal(t, values, cloned, "the clone must not be changed by new values")
+}
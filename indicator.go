@@ -1,17 +1,31 @@
 package outis
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 type Indicator struct {
+	mu        sync.Mutex
 	key       string
 	value     float64
 	createdAt time.Time
 }
 
-// NewIndicator creates a new indicator.
+// NewIndicator creates a new indicator, or returns the indicator
+// already created with the same key in the execution.
 func (ctx *ContextImpl) NewIndicator(key string) *Indicator {
-	indicator := &Indicator{key: key, value: 0, createdAt: time.Now()}
-	ctx.indicator = append(ctx.indicator, indicator)
+	ctx.measures.mu.Lock()
+	defer ctx.measures.mu.Unlock()
+
+	for _, indicator := range ctx.measures.indicators {
+		if indicator.key == key {
+			return indicator
+		}
+	}
+
+	indicator := &Indicator{key: key, value: 0, createdAt: ctx.Watcher.now()}
+	ctx.measures.indicators = append(ctx.measures.indicators, indicator)
 	return indicator
 }
 
@@ -19,13 +33,21 @@ func (ctx *ContextImpl) NewIndicator(key string) *Indicator {
 func (i *Indicator) GetKey() string { return i.key }
 
 // GetValue get the value of an indicator.
-func (i *Indicator) GetValue() float64 { return i.value }
+func (i *Indicator) GetValue() float64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.value
+}
 
 // GetCreatedAt get the creation date of an indicator.
 func (i *Indicator) GetCreatedAt() time.Time { return i.createdAt }
 
 // Inc increments the indicator data.
-func (i *Indicator) Inc() { i.value++ }
+func (i *Indicator) Inc() { i.Add(1) }
 
 // Add add a value to the indicator.
-func (i *Indicator) Add(value float64) { i.value += value }
+func (i *Indicator) Add(value float64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.value += value
+}
@@ -14,6 +14,18 @@ type IOutis interface {
 	Event(ctx Context, event Event)
 }
 
+// ErrorHandler is an optional interface of the IOutis implementations that handle the errors
+// of the executions, including the panics. Without it the errors are logged
+type ErrorHandler interface {
+	OnError(ctx Context, err error)
+}
+
+// RoutineExitHandler is an optional interface of the IOutis implementations notified when a routine
+// finishes, the error is nil when the routine was stopped. Without it the termination is logged
+type RoutineExitHandler interface {
+	OnRoutineExit(ctx Context, err error)
+}
+
 // ILogger methods for logging messages.
 type ILogger interface {
 	Level() LogLevel
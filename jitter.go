@@ -0,0 +1,78 @@
+package outis
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// Jitter defines the random delay added to each execution of a routine, spreading
+// the executions of routines and replicas scheduled for the same time
+type Jitter struct {
+	max           time.Duration
+	fraction      float64
+	deterministic bool
+}
+
+// JitterDuration delays each execution by a random duration up to max
+func JitterDuration(max time.Duration) Jitter {
+	return Jitter{max: max}
+}
+
+// JitterFraction delays each execution by a random duration up to the fraction,
+// between 0 and 1, of the interval until the following execution
+func JitterFraction(fraction float64) Jitter {
+	return Jitter{fraction: fraction}
+}
+
+// Deterministic defines that the delay is derived from the routine ID and from the scheduled
+// time, so it is the same between restarts and between the replicas of the watcher
+func (j Jitter) Deterministic() Jitter {
+	j.deterministic = true
+	return j
+}
+
+func (j Jitter) validate() error {
+	if j.max < 0 {
+		return errors.New("the jitter must not be negative")
+	}
+
+	if j.fraction < 0 || j.fraction > 1 {
+		return errors.New("the jitter fraction must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// delay returns the delay of the execution scheduled for the time
+func (j Jitter) delay(routineID ID, scheduledAt time.Time, interval time.Duration) time.Duration {
+	max := j.max
+	if j.fraction > 0 {
+		max = time.Duration(j.fraction * float64(interval))
+	}
+
+	if max <= 0 {
+		return 0
+	}
+
+	if !j.deterministic {
+		return time.Duration(rand.Int63n(int64(max)))
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(routineID.ToString() + ":" + strconv.FormatInt(scheduledAt.Unix(), 10)))
+	return time.Duration(hash.Sum64() % uint64(max))
+}
+
+// perturbation returns the time added to the execution scheduled for the time,
+// composed by the start offset and by the jitter of the routine
+func (ctx *ContextImpl) perturbation(scheduledAt time.Time) time.Duration {
+	perturbation := ctx.startOffset
+	if ctx.jitter != nil {
+		perturbation += ctx.jitter.delay(ctx.routineID, scheduledAt, ctx.next(scheduledAt).Sub(scheduledAt))
+	}
+
+	return perturbation
+}
@@ -0,0 +1,113 @@
+package outis_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+	"github.com/Brisanet/outis/outistest"
+)
+
+// runPerturbed executes the routine every minute with the fake clock during five minutes,
+// returning the delay of each execution in relation to its scheduled time
+func runPerturbed(t *testing.T, routineID outis.ID, opts ...outis.Option) []time.Duration {
+	t.Helper()
+
+	var (
+		clock = outistest.NewFakeClock(start)
+		watch = newClockWatcher(t, clock)
+
+		mu     sync.Mutex
+		delays []time.Duration
+	)
+
+	watch.Go(append([]outis.Option{
+		outis.WithID(routineID),
+		outis.WithName(routineID.ToString()),
+		outis.WithInterval(time.Minute),
+		outis.WithScript(func(ctx outis.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			// O horário agendado segue o intervalo, o atraso é aplicado somente à execução
+			assert.Equal(t, start.Add(time.Duration(len(delays)+1)*time.Minute), ctx.ScheduledAt())
+			delays = append(delays, clock.Now().Sub(ctx.ScheduledAt()))
+			return nil
+		}),
+	}, opts...)...)
+
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Minute)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// As execuções de 08:01 a 08:04 são feitas, a de 08:05 fica atrasada pelo offset
+	require.Len(t, delays, 4)
+	return delays
+}
+
+func TestStartOffset(t *testing.T) {
+	delays := runPerturbed(t, "routine", outis.WithStartOffset(5*time.Second))
+
+	for _, delay := range delays {
+		assert.Equal(t, 5*time.Second, delay)
+	}
+}
+
+func TestJitterWithinBounds(t *testing.T) {
+	t.Run("duration", func(t *testing.T) {
+		delays := runPerturbed(t, "routine",
+			outis.WithStartOffset(5*time.Second),
+			outis.WithJitter(outis.JitterDuration(10*time.Second)),
+		)
+
+		for _, delay := range delays {
+			assert.GreaterOrEqual(t, delay, 5*time.Second)
+			assert.Less(t, delay, 15*time.Second)
+		}
+	})
+
+	t.Run("fraction", func(t *testing.T) {
+		delays := runPerturbed(t, "routine", outis.WithJitter(outis.JitterFraction(0.5)))
+
+		for _, delay := range delays {
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.Less(t, delay, 30*time.Second)
+		}
+	})
+}
+
+func TestJitterDeterministic(t *testing.T) {
+	jitter := outis.WithJitter(outis.JitterDuration(10 * time.Second).Deterministic())
+
+	// O atraso é o mesmo entre reinícios e réplicas, pois depende somente da rotina e do horário
+	first := runPerturbed(t, "routine", jitter)
+	assert.Equal(t, first, runPerturbed(t, "routine", jitter))
+
+	for _, delay := range first {
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.Less(t, delay, 10*time.Second)
+	}
+
+	// Rotinas diferentes agendadas para o mesmo horário são espalhadas
+	assert.NotEqual(t, first, runPerturbed(t, "other", jitter))
+}
+
+func TestJitterValidate(t *testing.T) {
+	watch := newClockWatcher(t, outistest.NewFakeClock(start))
+
+	for name, opt := range map[string]outis.Option{
+		"negative duration": outis.WithJitter(outis.JitterDuration(-time.Second)),
+		"fraction above 1":  outis.WithJitter(outis.JitterFraction(1.5)),
+		"negative offset":   outis.WithStartOffset(-time.Second),
+	} {
+		_, err := watch.NewContext(outis.WithID("routine"), outis.WithName("routine"), outis.WithInterval(time.Minute),
+			outis.WithScript(func(outis.Context) error { return nil }), opt)
+		assert.Error(t, err, name)
+	}
+}
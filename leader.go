@@ -0,0 +1,180 @@
+package outis
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// EventLeadership defines the type of event sent
+// when the watcher gains or loses the leadership
+type EventLeadership struct {
+	Leader  bool
+	Owner   string
+	Watcher WatcherMetric
+}
+
+// leadership keeps the leadership state of a watcher, the routines
+// only execute while the watcher is the leader
+type leadership struct {
+	locker Locker
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	leader  bool
+	changed chan struct{}
+	term    chan struct{}
+	// waiters são as goroutines aguardando a mudança da liderança, marcadas como em
+	// execução no relógio ao serem acordadas
+	waiters int
+}
+
+func newLeadership(locker Locker, ttl time.Duration) *leadership {
+	return &leadership{locker: locker, ttl: ttl, changed: make(chan struct{})}
+}
+
+// IsLeader returns whether the watcher is the leader, a watcher
+// without leader election is always the leader
+func (watch *Watch) IsLeader() bool {
+	if watch.leadership == nil {
+		return true
+	}
+
+	watch.leadership.mu.Lock()
+	defer watch.leadership.mu.Unlock()
+
+	return watch.leadership.leader
+}
+
+// awaitLeadership blocks until the watcher is the leader, returning false when the context is done before
+func (watch *Watch) awaitLeadership(ctx context.Context) bool {
+	if watch.leadership == nil {
+		return ctx.Err() == nil
+	}
+
+	l := watch.leadership
+	for {
+		l.mu.Lock()
+		leader, changed := l.leader, l.changed
+		if !leader {
+			l.waiters++
+		}
+		l.mu.Unlock()
+
+		if leader {
+			return ctx.Err() == nil
+		}
+
+		watch.waiting()
+		select {
+		case <-ctx.Done():
+			// A goroutine só é marcada como em execução se a mudança da liderança não a acordou antes
+			l.mu.Lock()
+			if l.changed == changed {
+				l.waiters--
+				watch.running()
+			}
+			l.mu.Unlock()
+			return false
+		case <-changed:
+		}
+	}
+}
+
+// leadershipTerm returns a channel closed when the current leadership ends
+func (watch *Watch) leadershipTerm() <-chan struct{} {
+	if watch.leadership == nil {
+		return nil
+	}
+
+	watch.leadership.mu.Lock()
+	defer watch.leadership.mu.Unlock()
+
+	return watch.leadership.term
+}
+
+// setLeader changes the leadership state and sends the leadership event
+func (watch *Watch) setLeader(leader bool) {
+	l := watch.leadership
+
+	l.mu.Lock()
+	if l.leader == leader {
+		l.mu.Unlock()
+		return
+	}
+
+	l.leader = leader
+	if leader {
+		l.term = make(chan struct{})
+	} else {
+		close(l.term)
+	}
+	close(l.changed)
+	l.changed = make(chan struct{})
+	for ; l.waiters > 0; l.waiters-- {
+		watch.running()
+	}
+	l.mu.Unlock()
+
+	ctx := watch.newContext()
+	if leader {
+		ctx.LogInfo("Leadership acquired")
+	} else {
+		ctx.LogWarn("Leadership lost")
+	}
+
+	watch.outis.Event(ctx, EventLeadership{Leader: leader, Owner: watch.owner, Watcher: watch.metric()})
+}
+
+// elect keeps trying to acquire the leadership of the watcher, renewing
+// the lease while it is the leader, until the watcher is shut down
+func (watch *Watch) elect() {
+	var (
+		l         = watch.leadership
+		key       = watch.Id.ToString()
+		renewedAt time.Time
+	)
+
+	defer watch.waiting()
+
+	for {
+		if watch.IsLeader() {
+			renewed, err := l.locker.Refresh(watch.context, key, watch.owner, l.ttl)
+			if err != nil && watch.context.Err() == nil {
+				watch.log.Error(err, LogFields{"lock_key": key})
+			}
+
+			if renewed {
+				renewedAt = watch.now()
+			} else if err == nil || watch.since(renewedAt) >= l.ttl {
+				watch.setLeader(false)
+			}
+		} else {
+			acquired, err := l.locker.Lock(watch.context, key, watch.owner, l.ttl)
+			if err != nil && watch.context.Err() == nil {
+				watch.log.Error(err, LogFields{"lock_key": key})
+			}
+
+			if acquired {
+				renewedAt = watch.now()
+				watch.setLeader(true)
+			}
+		}
+
+		timer := watch.newTimer(l.ttl / 3)
+		watch.waiting()
+		select {
+		case <-watch.context.Done():
+			watch.running()
+			timer.Stop()
+			if watch.IsLeader() {
+				watch.setLeader(false)
+				if err := l.locker.Unlock(context.Background(), key, watch.owner); err != nil {
+					watch.log.Error(err, LogFields{"lock_key": key})
+				}
+			}
+			return
+		case <-timer.C():
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package outis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Locker defines a distributed lock with lease, used to ensure
+// that a routine is executed by a single process at a time
+type Locker interface {
+	// Lock tries to acquire the lock of the key for the owner during the ttl,
+	// returning false when the lock is held by another owner
+	Lock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	// Refresh extends the lease of a lock held by the owner,
+	// returning false when the lock is no longer held by the owner
+	Refresh(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	// Unlock releases the lock held by the owner
+	Unlock(ctx context.Context, key, owner string) error
+}
+
+// lockOptions defines the lock used by the routines of a watcher
+type lockOptions struct {
+	locker Locker
+	ttl    time.Duration
+	// held são os locks mantidos até o próximo horário após as execuções,
+	// assumidos pela próxima execução da rotina no mesmo processo
+	held sync.Map
+}
+
+// heldLock is a lock kept after an execution until the next slot of the schedule
+type heldLock struct {
+	owner string
+	until time.Time
+}
+
+// newOwner returns the identifier of the current process used as lock owner
+func newOwner() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), newID())
+}
+
+// lock acquires the lock of the routine and keeps it renewed until it is released.
+// If the lease can not be renewed before it expires, the execution context is cancelled.
+// The lock of a scheduled execution is kept until the next slot of the schedule, so that
+// the replicas whose schedule is not aligned, like intervals anchored on the start of
+// each replica or cron ticks with clock skew, do not execute the same slot again
+func (ctx *ContextImpl) lock() (release func(), acquired bool, err error) {
+	var (
+		lock  = ctx.Watcher.lock
+		key   = ctx.routineID.ToString()
+		owner = fmt.Sprintf("%s:%s", ctx.Watcher.owner, newID())
+		until time.Time
+	)
+
+	// A execução assume o lock mantido pela execução anterior da rotina neste processo
+	if value, ok := lock.held.LoadAndDelete(key); ok {
+		held := value.(heldLock)
+		owner, until = held.owner, held.until
+	}
+
+	if acquired, err = lock.locker.Lock(ctx.context, key, owner, lock.ttl); err != nil || !acquired {
+		return nil, acquired, err
+	}
+
+	var (
+		stop = make(chan struct{})
+		done = make(chan struct{})
+	)
+
+	ctx.Watcher.running()
+	go func() {
+		defer close(done)
+		defer ctx.Watcher.waiting()
+
+		renewedAt := ctx.Watcher.now()
+		for {
+			timer := ctx.Watcher.newTimer(lock.ttl / 3)
+			ctx.Watcher.waiting()
+			select {
+			case <-stop:
+				ctx.Watcher.running()
+				timer.Stop()
+				return
+			case <-timer.C():
+				renewed, err := lock.locker.Refresh(ctx.context, key, owner, lock.ttl)
+				if err != nil {
+					ctx.LogError(err, LogFields{"lock_key": key})
+				}
+
+				if renewed {
+					renewedAt = ctx.Watcher.now()
+					continue
+				}
+
+				if err == nil || ctx.Watcher.since(renewedAt) >= lock.ttl {
+					ctx.LogWarn("Lock lost, cancelling execution", LogFields{"lock_key": key})
+					ctx.contextCancelFunc()
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+
+		if next := ctx.lockUntil(); next.After(until) {
+			until = next
+		}
+
+		if hold := until.Sub(ctx.Watcher.now()); hold > 0 {
+			renewed, err := lock.locker.Refresh(context.Background(), key, owner, hold)
+			if err != nil {
+				ctx.LogError(err, LogFields{"lock_key": key})
+			}
+			if renewed {
+				lock.held.Store(key, heldLock{owner: owner, until: until})
+			}
+			return
+		}
+
+		if err := lock.locker.Unlock(context.Background(), key, owner); err != nil {
+			ctx.LogError(err, LogFields{"lock_key": key})
+		}
+	}, true, nil
+}
+
+// claimCatchUp acquires the claim of the catch-up of the routine, so that the replicas sharing the
+// history do not catch up the same slots. A single claim is kept for each routine, renewed by every
+// catch-up execution of the replica that holds it, until the next slot of the schedule, when the
+// executions are expected in the history. The expired claim is taken over by the next catch-up,
+// so the claims do not accumulate in the locker
+func (ctx *ContextImpl) claimCatchUp() (bool, error) {
+	var (
+		lock = ctx.Watcher.lock
+		now  = ctx.Watcher.now()
+		ttl  = lock.ttl
+	)
+
+	if next := ctx.next(now); next.After(now) {
+		ttl += next.Sub(now)
+	}
+
+	return lock.locker.Lock(ctx.context, ctx.routineID.ToString()+"@catch-up", ctx.Watcher.owner, ttl)
+}
+
+// lockUntil returns until when the lock is kept after the execution, the next slot of the schedule.
+// The executions out of the schedule, triggered manually or without loop, do not keep the lock
+func (ctx *ContextImpl) lockUntil() time.Time {
+	if ctx.triggered || ctx.notUseLoop || ctx.scheduledAt.IsZero() {
+		return time.Time{}
+	}
+
+	return ctx.next(ctx.scheduledAt)
+}
@@ -0,0 +1,145 @@
+package outis
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLocker verifies the behavior expected of every Locker implementation
+func testLocker(t *testing.T, newLocker func(t *testing.T) Locker) {
+	ctx := context.Background()
+
+	t.Run("contention between owners", func(t *testing.T) {
+		locker := newLocker(t)
+
+		acquired, err := locker.Lock(ctx, "routine", "owner-a", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, err = locker.Lock(ctx, "routine", "owner-b", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired, "the lock held by another owner must not be acquired")
+
+		acquired, err = locker.Lock(ctx, "routine", "owner-a", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired, "the owner must acquire its own lock again")
+
+		renewed, err := locker.Refresh(ctx, "routine", "owner-b", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, renewed, "a lock held by another owner must not be renewed")
+
+		require.NoError(t, locker.Unlock(ctx, "routine", "owner-b"))
+		acquired, err = locker.Lock(ctx, "routine", "owner-b", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired, "the unlock of another owner must not release the lock")
+
+		require.NoError(t, locker.Unlock(ctx, "routine", "owner-a"))
+		acquired, err = locker.Lock(ctx, "routine", "owner-b", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired, "the lock must be acquired after released")
+	})
+
+	t.Run("independent keys", func(t *testing.T) {
+		locker := newLocker(t)
+
+		for _, key := range []string{"a/b", "a_b", "a%2Fb", "a:b"} {
+			acquired, err := locker.Lock(ctx, key, "owner-"+key, time.Minute)
+			require.NoError(t, err)
+			assert.True(t, acquired, "the lock of the key %q must be independent", key)
+		}
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		locker := newLocker(t)
+
+		acquired, err := locker.Lock(ctx, "routine", "owner-a", 50*time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		time.Sleep(100 * time.Millisecond)
+
+		renewed, err := locker.Refresh(ctx, "routine", "owner-a", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, renewed, "the owner renews the lease while no other owner acquired it")
+
+		acquired, err = locker.Lock(ctx, "routine", "owner-b", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired, "the renewed lease must not be acquired")
+
+		_, err = locker.Refresh(ctx, "routine", "owner-a", 50*time.Millisecond)
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+
+		acquired, err = locker.Lock(ctx, "routine", "owner-b", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired, "the expired lease must be acquired by another owner")
+
+		renewed, err = locker.Refresh(ctx, "routine", "owner-a", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, renewed, "the previous owner must not renew a lease acquired by another owner")
+	})
+}
+
+func TestExecuteKeepsLockUntilNextSlot(t *testing.T) {
+	locker, err := NewFileLocker(filepath.Join(t.TempDir(), "locks"))
+	require.NoError(t, err)
+
+	var executions int32
+	newContext := func(t *testing.T) *ContextImpl {
+		watch := newTestWatcher(t, DistributedLock(locker, time.Minute))
+		ctx, err := watch.NewContext(
+			WithID("routine"),
+			WithName("routine"),
+			WithInterval(time.Hour),
+			WithScript(func(Context) error {
+				atomic.AddInt32(&executions, 1)
+				return nil
+			}),
+		)
+		require.NoError(t, err)
+		return ctx
+	}
+
+	var (
+		replicaA = newContext(t)
+		replicaB = newContext(t)
+	)
+
+	require.NoError(t, replicaA.Execute())
+	require.NoError(t, replicaB.Execute())
+	assert.EqualValues(t, 1, atomic.LoadInt32(&executions), "the slot must be executed by a single replica")
+
+	// A execução manual assume o lock mantido no mesmo processo e o mantém até o próximo horário
+	triggered := replicaA.newExecution()
+	triggered.scheduledAt, triggered.triggered = replicaA.Watcher.now(), true
+	require.NoError(t, triggered.Execute())
+	assert.EqualValues(t, 2, atomic.LoadInt32(&executions))
+
+	require.NoError(t, replicaB.Execute())
+	assert.EqualValues(t, 2, atomic.LoadInt32(&executions), "the manual execution must not release the lock of the slot")
+
+	next := replicaA.newExecution()
+	next.scheduledAt = replicaA.scheduledAt.Add(time.Hour)
+	require.NoError(t, next.Execute())
+	assert.EqualValues(t, 3, atomic.LoadInt32(&executions), "the replica must take over its own lock in the next slot")
+}
+
+func TestExecuteReleasesLockWithoutNextSlot(t *testing.T) {
+	locker, err := NewFileLocker(t.TempDir())
+	require.NoError(t, err)
+
+	watch := newTestWatcher(t, DistributedLock(locker, time.Minute))
+	ctx, err := watch.NewContext(WithID("routine"), WithName("routine"), WithNotUseLoop(), WithScript(func(Context) error { return nil }))
+	require.NoError(t, err)
+	require.NoError(t, ctx.Execute())
+
+	acquired, err := locker.Lock(context.Background(), "routine", "other", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the lock of an execution without loop must be released")
+}
@@ -0,0 +1,52 @@
+package outis
+
+import "sync"
+
+// measures keeps the indicators and histograms recorded during an execution and guards
+// the changes of the execution metadata, it is shared by every copy of the execution context
+type measures struct {
+	mu         sync.Mutex
+	indicators []*Indicator
+	histograms []*Histogram
+}
+
+func newMeasures() *measures {
+	return &measures{indicators: make([]*Indicator, 0), histograms: make([]*Histogram, 0)}
+}
+
+// snapshot returns a copy of the metadata and the recorded indicators, and
+// copies of the histograms that are not changed by values added later
+func (m *measures) snapshot(metadata Metadata) (Metadata, []*Indicator, []*Histogram) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copyMetadata := make(Metadata, len(metadata))
+	for key, value := range metadata {
+		copyMetadata[key] = value
+	}
+
+	histograms := make([]*Histogram, 0, len(m.histograms))
+	for _, histogram := range m.histograms {
+		histograms = append(histograms, histogram.Clone())
+	}
+
+	return copyMetadata, append([]*Indicator(nil), m.indicators...), histograms
+}
+
+// Metadata returns a copy of the metadata added to the execution
+func (ctx *ContextImpl) Metadata() Metadata {
+	metadata, _, _ := ctx.measures.snapshot(ctx.metadata)
+	return metadata
+}
+
+// Indicators returns the indicators created in the execution
+func (ctx *ContextImpl) Indicators() []*Indicator {
+	_, indicators, _ := ctx.measures.snapshot(nil)
+	return indicators
+}
+
+// Histograms returns copies of the histograms created in the execution
+func (ctx *ContextImpl) Histograms() []*Histogram {
+	_, _, histograms := ctx.measures.snapshot(nil)
+	return histograms
+}
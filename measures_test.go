@@ -0,0 +1,82 @@
+package outis_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+	"github.com/Brisanet/outis/outistest"
+)
+
+func TestContextMeasures(t *testing.T) {
+	var (
+		recorder = outistest.NewRecorder(nil)
+		watch    = outis.Watcher("watcher", "watcher", outis.Logger(outistest.NewLogRecorder()), outis.Impl(recorder))
+		attempts int
+	)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+
+	ctx, err := watch.NewContext(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithScript(func(ctx outis.Context) error {
+			// As medidas registradas na cópia do contexto, por várias goroutines, chegam ao evento da execução
+			copied := ctx.Copy()
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						copied.NewIndicator("processed").Inc()
+						copied.NewHistogram("size", outis.WithBuckets(10, 100)).Add(float64(j))
+					}
+					copied.AddSingleMetadata("worker_"+strconv.Itoa(i), i)
+				}(i)
+			}
+			wg.Wait()
+
+			return ctx.Retry(2).Attempt(func() error {
+				attempts++
+				ctx.NewIndicator("attempts").Inc()
+				if attempts == 1 {
+					return errors.New("failure")
+				}
+				return nil
+			})
+		}),
+	)
+	require.NoError(t, err)
+
+	// Cada execução começa com as medidas zeradas
+	for execution := 1; execution <= 2; execution++ {
+		attempts = 0
+		require.NoError(t, ctx.Execute())
+
+		metrics := recorder.Metrics()
+		require.Len(t, metrics, execution)
+		metric := metrics[execution-1]
+
+		indicators := make(map[string]float64)
+		for _, indicator := range metric.Indicators {
+			indicators[indicator.GetKey()] = indicator.GetValue()
+		}
+		assert.Equal(t, map[string]float64{"processed": 1000, "attempts": 2}, indicators)
+
+		require.Len(t, metric.Histograms, 1)
+		snapshot := metric.Histograms[0].Snapshot()
+		assert.Equal(t, "size", snapshot.Key)
+		assert.EqualValues(t, 1000, snapshot.Count)
+		assert.Equal(t, []outis.HistogramBucket{{UpperBound: 10, Count: 110}, {UpperBound: 100, Count: 1000}}, snapshot.Buckets)
+
+		assert.Len(t, metric.Metadata, 10)
+		assert.Equal(t, 3, metric.Metadata["worker_3"])
+	}
+}
@@ -21,15 +21,45 @@ func (md Metadata) GetBytes() []byte {
 
 // EventMetric defines the type of metric sent in the event
 type EventMetric struct {
-	ID         string
-	Latency    time.Duration
-	StartedAt  time.Time
-	FinishedAt time.Time
+	ID          string
+	Attempt     int
+	Latency     time.Duration
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Error       string
+	Timeout     bool
+	Overlap     OverlapMetric
+	ScheduledAt time.Time
+	CatchUp     bool
+	Triggered   bool
+	Watcher     WatcherMetric
+	Routine     RoutineMetric
+	Metadata    Metadata
+	Indicators  []*Indicator
+	Histograms  []*Histogram
+}
+
+// EventRetry defines the type of event sent
+// when an execution attempt fails
+type EventRetry struct {
+	ID       string
+	Attempt  int
+	Delay    time.Duration
+	Err      error
+	Retrying bool
+	Watcher  WatcherMetric
+	Routine  RoutineMetric
+}
+
+// EventRestart defines the type of event sent
+// when the loop of a routine finishes and the restart policy is applied
+type EventRestart struct {
+	Restart    int
+	Delay      time.Duration
+	Err        error
+	Restarting bool
 	Watcher    WatcherMetric
 	Routine    RoutineMetric
-	Metadata   Metadata
-	Indicators []*Indicator
-	Histograms []*Histogram
 }
 
 // RoutineMetric defines the type of metric
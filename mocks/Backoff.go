@@ -0,0 +1,83 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Backoff is an autogenerated mock type for the Backoff type
+type Backoff struct {
+	mock.Mock
+}
+
+type Backoff_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Backoff) EXPECT() *Backoff_Expecter {
+	return &Backoff_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: attempt, previous
+func (_m *Backoff) Execute(attempt int, previous time.Duration) time.Duration {
+	ret := _m.Called(attempt, previous)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func(int, time.Duration) time.Duration); ok {
+		r0 = rf(attempt, previous)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// Backoff_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type Backoff_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - attempt int
+//   - previous time.Duration
+func (_e *Backoff_Expecter) Execute(attempt interface{}, previous interface{}) *Backoff_Execute_Call {
+	return &Backoff_Execute_Call{Call: _e.mock.On("Execute", attempt, previous)}
+}
+
+func (_c *Backoff_Execute_Call) Run(run func(attempt int, previous time.Duration)) *Backoff_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Backoff_Execute_Call) Return(_a0 time.Duration) *Backoff_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backoff_Execute_Call) RunAndReturn(run func(int, time.Duration) time.Duration) *Backoff_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBackoff creates a new instance of Backoff. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBackoff(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Backoff {
+	mock := &Backoff{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	outis "github.com/Brisanet/outis"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ClockTracker is an autogenerated mock type for the ClockTracker type
+type ClockTracker struct {
+	mock.Mock
+}
+
+type ClockTracker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ClockTracker) EXPECT() *ClockTracker_Expecter {
+	return &ClockTracker_Expecter{mock: &_m.Mock}
+}
+
+// NewTrackedTimer provides a mock function with given fields: d
+func (_m *ClockTracker) NewTrackedTimer(d time.Duration) outis.ITimer {
+	ret := _m.Called(d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewTrackedTimer")
+	}
+
+	var r0 outis.ITimer
+	if rf, ok := ret.Get(0).(func(time.Duration) outis.ITimer); ok {
+		r0 = rf(d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(outis.ITimer)
+		}
+	}
+
+	return r0
+}
+
+// ClockTracker_NewTrackedTimer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewTrackedTimer'
+type ClockTracker_NewTrackedTimer_Call struct {
+	*mock.Call
+}
+
+// NewTrackedTimer is a helper method to define mock.On call
+//   - d time.Duration
+func (_e *ClockTracker_Expecter) NewTrackedTimer(d interface{}) *ClockTracker_NewTrackedTimer_Call {
+	return &ClockTracker_NewTrackedTimer_Call{Call: _e.mock.On("NewTrackedTimer", d)}
+}
+
+func (_c *ClockTracker_NewTrackedTimer_Call) Run(run func(d time.Duration)) *ClockTracker_NewTrackedTimer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *ClockTracker_NewTrackedTimer_Call) Return(_a0 outis.ITimer) *ClockTracker_NewTrackedTimer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ClockTracker_NewTrackedTimer_Call) RunAndReturn(run func(time.Duration) outis.ITimer) *ClockTracker_NewTrackedTimer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Running provides a mock function with no fields
+func (_m *ClockTracker) Running() {
+	_m.Called()
+}
+
+// ClockTracker_Running_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Running'
+type ClockTracker_Running_Call struct {
+	*mock.Call
+}
+
+// Running is a helper method to define mock.On call
+func (_e *ClockTracker_Expecter) Running() *ClockTracker_Running_Call {
+	return &ClockTracker_Running_Call{Call: _e.mock.On("Running")}
+}
+
+func (_c *ClockTracker_Running_Call) Run(run func()) *ClockTracker_Running_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ClockTracker_Running_Call) Return() *ClockTracker_Running_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ClockTracker_Running_Call) RunAndReturn(run func()) *ClockTracker_Running_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Waiting provides a mock function with no fields
+func (_m *ClockTracker) Waiting() {
+	_m.Called()
+}
+
+// ClockTracker_Waiting_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Waiting'
+type ClockTracker_Waiting_Call struct {
+	*mock.Call
+}
+
+// Waiting is a helper method to define mock.On call
+func (_e *ClockTracker_Expecter) Waiting() *ClockTracker_Waiting_Call {
+	return &ClockTracker_Waiting_Call{Call: _e.mock.On("Waiting")}
+}
+
+func (_c *ClockTracker_Waiting_Call) Run(run func()) *ClockTracker_Waiting_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ClockTracker_Waiting_Call) Return() *ClockTracker_Waiting_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ClockTracker_Waiting_Call) RunAndReturn(run func()) *ClockTracker_Waiting_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewClockTracker creates a new instance of ClockTracker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClockTracker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClockTracker {
+	mock := &ClockTracker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
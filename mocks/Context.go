@@ -7,6 +7,8 @@ import (
 
 	outis "github.com/Brisanet/outis"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Context is an autogenerated mock type for the Context type
@@ -119,6 +121,51 @@ func (_c *Context_AddSingleMetadata_Call) RunAndReturn(run func(string, interfac
 	return _c
 }
 
+// Attempt provides a mock function with no fields
+func (_m *Context) Attempt() int {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Attempt")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// Context_Attempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Attempt'
+type Context_Attempt_Call struct {
+	*mock.Call
+}
+
+// Attempt is a helper method to define mock.On call
+func (_e *Context_Expecter) Attempt() *Context_Attempt_Call {
+	return &Context_Attempt_Call{Call: _e.mock.On("Attempt")}
+}
+
+func (_c *Context_Attempt_Call) Run(run func()) *Context_Attempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Context_Attempt_Call) Return(_a0 int) *Context_Attempt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Context_Attempt_Call) RunAndReturn(run func() int) *Context_Attempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Cancel provides a mock function with no fields
 func (_m *Context) Cancel() {
 	_m.Called()
@@ -822,6 +869,210 @@ func (_c *Context_Name_Call) RunAndReturn(run func() string) *Context_Name_Call
 	return _c
 }
 
+// NewHistogram provides a mock function with given fields: key, opts
+func (_m *Context) NewHistogram(key string, opts ...outis.HistogramOption) *outis.Histogram {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, key)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewHistogram")
+	}
+
+	var r0 *outis.Histogram
+	if rf, ok := ret.Get(0).(func(string, ...outis.HistogramOption) *outis.Histogram); ok {
+		r0 = rf(key, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*outis.Histogram)
+		}
+	}
+
+	return r0
+}
+
+// Context_NewHistogram_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewHistogram'
+type Context_NewHistogram_Call struct {
+	*mock.Call
+}
+
+// NewHistogram is a helper method to define mock.On call
+//   - key string
+//   - opts ...outis.HistogramOption
+func (_e *Context_Expecter) NewHistogram(key interface{}, opts ...interface{}) *Context_NewHistogram_Call {
+	return &Context_NewHistogram_Call{Call: _e.mock.On("NewHistogram",
+		append([]interface{}{key}, opts...)...)}
+}
+
+func (_c *Context_NewHistogram_Call) Run(run func(key string, opts ...outis.HistogramOption)) *Context_NewHistogram_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]outis.HistogramOption, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(outis.HistogramOption)
+			}
+		}
+		run(args[0].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *Context_NewHistogram_Call) Return(_a0 *outis.Histogram) *Context_NewHistogram_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Context_NewHistogram_Call) RunAndReturn(run func(string, ...outis.HistogramOption) *outis.Histogram) *Context_NewHistogram_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewIndicator provides a mock function with given fields: key
+func (_m *Context) NewIndicator(key string) *outis.Indicator {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewIndicator")
+	}
+
+	var r0 *outis.Indicator
+	if rf, ok := ret.Get(0).(func(string) *outis.Indicator); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*outis.Indicator)
+		}
+	}
+
+	return r0
+}
+
+// Context_NewIndicator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewIndicator'
+type Context_NewIndicator_Call struct {
+	*mock.Call
+}
+
+// NewIndicator is a helper method to define mock.On call
+//   - key string
+func (_e *Context_Expecter) NewIndicator(key interface{}) *Context_NewIndicator_Call {
+	return &Context_NewIndicator_Call{Call: _e.mock.On("NewIndicator", key)}
+}
+
+func (_c *Context_NewIndicator_Call) Run(run func(key string)) *Context_NewIndicator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Context_NewIndicator_Call) Return(_a0 *outis.Indicator) *Context_NewIndicator_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Context_NewIndicator_Call) RunAndReturn(run func(string) *outis.Indicator) *Context_NewIndicator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PreviousScheduledAt provides a mock function with no fields
+func (_m *Context) PreviousScheduledAt() time.Time {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviousScheduledAt")
+	}
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// Context_PreviousScheduledAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PreviousScheduledAt'
+type Context_PreviousScheduledAt_Call struct {
+	*mock.Call
+}
+
+// PreviousScheduledAt is a helper method to define mock.On call
+func (_e *Context_Expecter) PreviousScheduledAt() *Context_PreviousScheduledAt_Call {
+	return &Context_PreviousScheduledAt_Call{Call: _e.mock.On("PreviousScheduledAt")}
+}
+
+func (_c *Context_PreviousScheduledAt_Call) Run(run func()) *Context_PreviousScheduledAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Context_PreviousScheduledAt_Call) Return(_a0 time.Time) *Context_PreviousScheduledAt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Context_PreviousScheduledAt_Call) RunAndReturn(run func() time.Time) *Context_PreviousScheduledAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Retry provides a mock function with given fields: retries
+func (_m *Context) Retry(retries int8) *outis.Retrier {
+	ret := _m.Called(retries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Retry")
+	}
+
+	var r0 *outis.Retrier
+	if rf, ok := ret.Get(0).(func(int8) *outis.Retrier); ok {
+		r0 = rf(retries)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*outis.Retrier)
+		}
+	}
+
+	return r0
+}
+
+// Context_Retry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Retry'
+type Context_Retry_Call struct {
+	*mock.Call
+}
+
+// Retry is a helper method to define mock.On call
+//   - retries int8
+func (_e *Context_Expecter) Retry(retries interface{}) *Context_Retry_Call {
+	return &Context_Retry_Call{Call: _e.mock.On("Retry", retries)}
+}
+
+func (_c *Context_Retry_Call) Run(run func(retries int8)) *Context_Retry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int8))
+	})
+	return _c
+}
+
+func (_c *Context_Retry_Call) Return(_a0 *outis.Retrier) *Context_Retry_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Context_Retry_Call) RunAndReturn(run func(int8) *outis.Retrier) *Context_Retry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RoutineID provides a mock function with no fields
 func (_m *Context) RoutineID() outis.ID {
 	ret := _m.Called()
@@ -867,6 +1118,51 @@ func (_c *Context_RoutineID_Call) RunAndReturn(run func() outis.ID) *Context_Rou
 	return _c
 }
 
+// ScheduledAt provides a mock function with no fields
+func (_m *Context) ScheduledAt() time.Time {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduledAt")
+	}
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// Context_ScheduledAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScheduledAt'
+type Context_ScheduledAt_Call struct {
+	*mock.Call
+}
+
+// ScheduledAt is a helper method to define mock.On call
+func (_e *Context_Expecter) ScheduledAt() *Context_ScheduledAt_Call {
+	return &Context_ScheduledAt_Call{Call: _e.mock.On("ScheduledAt")}
+}
+
+func (_c *Context_ScheduledAt_Call) Run(run func()) *Context_ScheduledAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Context_ScheduledAt_Call) Return(_a0 time.Time) *Context_ScheduledAt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Context_ScheduledAt_Call) RunAndReturn(run func() time.Time) *Context_ScheduledAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewContext creates a new instance of Context. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewContext(t interface {
@@ -0,0 +1,69 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	outis "github.com/Brisanet/outis"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ErrorHandler is an autogenerated mock type for the ErrorHandler type
+type ErrorHandler struct {
+	mock.Mock
+}
+
+type ErrorHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ErrorHandler) EXPECT() *ErrorHandler_Expecter {
+	return &ErrorHandler_Expecter{mock: &_m.Mock}
+}
+
+// OnError provides a mock function with given fields: ctx, err
+func (_m *ErrorHandler) OnError(ctx outis.Context, err error) {
+	_m.Called(ctx, err)
+}
+
+// ErrorHandler_OnError_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OnError'
+type ErrorHandler_OnError_Call struct {
+	*mock.Call
+}
+
+// OnError is a helper method to define mock.On call
+//   - ctx outis.Context
+//   - err error
+func (_e *ErrorHandler_Expecter) OnError(ctx interface{}, err interface{}) *ErrorHandler_OnError_Call {
+	return &ErrorHandler_OnError_Call{Call: _e.mock.On("OnError", ctx, err)}
+}
+
+func (_c *ErrorHandler_OnError_Call) Run(run func(ctx outis.Context, err error)) *ErrorHandler_OnError_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(outis.Context), args[1].(error))
+	})
+	return _c
+}
+
+func (_c *ErrorHandler_OnError_Call) Return() *ErrorHandler_OnError_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ErrorHandler_OnError_Call) RunAndReturn(run func(outis.Context, error)) *ErrorHandler_OnError_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewErrorHandler creates a new instance of ErrorHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewErrorHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ErrorHandler {
+	mock := &ErrorHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,202 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	context "context"
+
+	outis "github.com/Brisanet/outis"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ExecutionStore is an autogenerated mock type for the ExecutionStore type
+type ExecutionStore struct {
+	mock.Mock
+}
+
+type ExecutionStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ExecutionStore) EXPECT() *ExecutionStore_Expecter {
+	return &ExecutionStore_Expecter{mock: &_m.Mock}
+}
+
+// Prune provides a mock function with given fields: ctx, before
+func (_m *ExecutionStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Prune")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExecutionStore_Prune_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Prune'
+type ExecutionStore_Prune_Call struct {
+	*mock.Call
+}
+
+// Prune is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *ExecutionStore_Expecter) Prune(ctx interface{}, before interface{}) *ExecutionStore_Prune_Call {
+	return &ExecutionStore_Prune_Call{Call: _e.mock.On("Prune", ctx, before)}
+}
+
+func (_c *ExecutionStore_Prune_Call) Run(run func(ctx context.Context, before time.Time)) *ExecutionStore_Prune_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ExecutionStore_Prune_Call) Return(_a0 int, _a1 error) *ExecutionStore_Prune_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExecutionStore_Prune_Call) RunAndReturn(run func(context.Context, time.Time) (int, error)) *ExecutionStore_Prune_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Query provides a mock function with given fields: ctx, query
+func (_m *ExecutionStore) Query(ctx context.Context, query outis.ExecutionQuery) ([]outis.Execution, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Query")
+	}
+
+	var r0 []outis.Execution
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, outis.ExecutionQuery) ([]outis.Execution, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, outis.ExecutionQuery) []outis.Execution); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]outis.Execution)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, outis.ExecutionQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExecutionStore_Query_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Query'
+type ExecutionStore_Query_Call struct {
+	*mock.Call
+}
+
+// Query is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query outis.ExecutionQuery
+func (_e *ExecutionStore_Expecter) Query(ctx interface{}, query interface{}) *ExecutionStore_Query_Call {
+	return &ExecutionStore_Query_Call{Call: _e.mock.On("Query", ctx, query)}
+}
+
+func (_c *ExecutionStore_Query_Call) Run(run func(ctx context.Context, query outis.ExecutionQuery)) *ExecutionStore_Query_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(outis.ExecutionQuery))
+	})
+	return _c
+}
+
+func (_c *ExecutionStore_Query_Call) Return(_a0 []outis.Execution, _a1 error) *ExecutionStore_Query_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ExecutionStore_Query_Call) RunAndReturn(run func(context.Context, outis.ExecutionQuery) ([]outis.Execution, error)) *ExecutionStore_Query_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, execution
+func (_m *ExecutionStore) Save(ctx context.Context, execution outis.Execution) error {
+	ret := _m.Called(ctx, execution)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, outis.Execution) error); ok {
+		r0 = rf(ctx, execution)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExecutionStore_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type ExecutionStore_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - execution outis.Execution
+func (_e *ExecutionStore_Expecter) Save(ctx interface{}, execution interface{}) *ExecutionStore_Save_Call {
+	return &ExecutionStore_Save_Call{Call: _e.mock.On("Save", ctx, execution)}
+}
+
+func (_c *ExecutionStore_Save_Call) Run(run func(ctx context.Context, execution outis.Execution)) *ExecutionStore_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(outis.Execution))
+	})
+	return _c
+}
+
+func (_c *ExecutionStore_Save_Call) Return(_a0 error) *ExecutionStore_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ExecutionStore_Save_Call) RunAndReturn(run func(context.Context, outis.Execution) error) *ExecutionStore_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExecutionStore creates a new instance of ExecutionStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExecutionStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExecutionStore {
+	mock := &ExecutionStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	outis "github.com/Brisanet/outis"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HistogramOption is an autogenerated mock type for the HistogramOption type
+type HistogramOption struct {
+	mock.Mock
+}
+
+type HistogramOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *HistogramOption) EXPECT() *HistogramOption_Expecter {
+	return &HistogramOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *HistogramOption) Execute(_a0 *outis.Histogram) {
+	_m.Called(_a0)
+}
+
+// HistogramOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type HistogramOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *outis.Histogram
+func (_e *HistogramOption_Expecter) Execute(_a0 interface{}) *HistogramOption_Execute_Call {
+	return &HistogramOption_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *HistogramOption_Execute_Call) Run(run func(_a0 *outis.Histogram)) *HistogramOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*outis.Histogram))
+	})
+	return _c
+}
+
+func (_c *HistogramOption_Execute_Call) Return() *HistogramOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *HistogramOption_Execute_Call) RunAndReturn(run func(*outis.Histogram)) *HistogramOption_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewHistogramOption creates a new instance of HistogramOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHistogramOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HistogramOption {
+	mock := &HistogramOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,130 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	outis "github.com/Brisanet/outis"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IClock is an autogenerated mock type for the IClock type
+type IClock struct {
+	mock.Mock
+}
+
+type IClock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *IClock) EXPECT() *IClock_Expecter {
+	return &IClock_Expecter{mock: &_m.Mock}
+}
+
+// NewTimer provides a mock function with given fields: d
+func (_m *IClock) NewTimer(d time.Duration) outis.ITimer {
+	ret := _m.Called(d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewTimer")
+	}
+
+	var r0 outis.ITimer
+	if rf, ok := ret.Get(0).(func(time.Duration) outis.ITimer); ok {
+		r0 = rf(d)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(outis.ITimer)
+		}
+	}
+
+	return r0
+}
+
+// IClock_NewTimer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewTimer'
+type IClock_NewTimer_Call struct {
+	*mock.Call
+}
+
+// NewTimer is a helper method to define mock.On call
+//   - d time.Duration
+func (_e *IClock_Expecter) NewTimer(d interface{}) *IClock_NewTimer_Call {
+	return &IClock_NewTimer_Call{Call: _e.mock.On("NewTimer", d)}
+}
+
+func (_c *IClock_NewTimer_Call) Run(run func(d time.Duration)) *IClock_NewTimer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *IClock_NewTimer_Call) Return(_a0 outis.ITimer) *IClock_NewTimer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IClock_NewTimer_Call) RunAndReturn(run func(time.Duration) outis.ITimer) *IClock_NewTimer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Now provides a mock function with no fields
+func (_m *IClock) Now() time.Time {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Now")
+	}
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// IClock_Now_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Now'
+type IClock_Now_Call struct {
+	*mock.Call
+}
+
+// Now is a helper method to define mock.On call
+func (_e *IClock_Expecter) Now() *IClock_Now_Call {
+	return &IClock_Now_Call{Call: _e.mock.On("Now")}
+}
+
+func (_c *IClock_Now_Call) Run(run func()) *IClock_Now_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *IClock_Now_Call) Return(_a0 time.Time) *IClock_Now_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IClock_Now_Call) RunAndReturn(run func() time.Time) *IClock_Now_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewIClock creates a new instance of IClock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIClock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IClock {
+	mock := &IClock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
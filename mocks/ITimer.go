@@ -0,0 +1,128 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ITimer is an autogenerated mock type for the ITimer type
+type ITimer struct {
+	mock.Mock
+}
+
+type ITimer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ITimer) EXPECT() *ITimer_Expecter {
+	return &ITimer_Expecter{mock: &_m.Mock}
+}
+
+// C provides a mock function with no fields
+func (_m *ITimer) C() <-chan time.Time {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for C")
+	}
+
+	var r0 <-chan time.Time
+	if rf, ok := ret.Get(0).(func() <-chan time.Time); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan time.Time)
+		}
+	}
+
+	return r0
+}
+
+// ITimer_C_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'C'
+type ITimer_C_Call struct {
+	*mock.Call
+}
+
+// C is a helper method to define mock.On call
+func (_e *ITimer_Expecter) C() *ITimer_C_Call {
+	return &ITimer_C_Call{Call: _e.mock.On("C")}
+}
+
+func (_c *ITimer_C_Call) Run(run func()) *ITimer_C_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ITimer_C_Call) Return(_a0 <-chan time.Time) *ITimer_C_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ITimer_C_Call) RunAndReturn(run func() <-chan time.Time) *ITimer_C_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stop provides a mock function with no fields
+func (_m *ITimer) Stop() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stop")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// ITimer_Stop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stop'
+type ITimer_Stop_Call struct {
+	*mock.Call
+}
+
+// Stop is a helper method to define mock.On call
+func (_e *ITimer_Expecter) Stop() *ITimer_Stop_Call {
+	return &ITimer_Stop_Call{Call: _e.mock.On("Stop")}
+}
+
+func (_c *ITimer_Stop_Call) Run(run func()) *ITimer_Stop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ITimer_Stop_Call) Return(_a0 bool) *ITimer_Stop_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ITimer_Stop_Call) RunAndReturn(run func() bool) *ITimer_Stop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewITimer creates a new instance of ITimer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewITimer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ITimer {
+	mock := &ITimer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,204 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Locker is an autogenerated mock type for the Locker type
+type Locker struct {
+	mock.Mock
+}
+
+type Locker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Locker) EXPECT() *Locker_Expecter {
+	return &Locker_Expecter{mock: &_m.Mock}
+}
+
+// Lock provides a mock function with given fields: ctx, key, owner, ttl
+func (_m *Locker) Lock(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, owner, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, key, owner, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) bool); ok {
+		r0 = rf(ctx, key, owner, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, owner, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Locker_Lock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lock'
+type Locker_Lock_Call struct {
+	*mock.Call
+}
+
+// Lock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - owner string
+//   - ttl time.Duration
+func (_e *Locker_Expecter) Lock(ctx interface{}, key interface{}, owner interface{}, ttl interface{}) *Locker_Lock_Call {
+	return &Locker_Lock_Call{Call: _e.mock.On("Lock", ctx, key, owner, ttl)}
+}
+
+func (_c *Locker_Lock_Call) Run(run func(ctx context.Context, key string, owner string, ttl time.Duration)) *Locker_Lock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Locker_Lock_Call) Return(_a0 bool, _a1 error) *Locker_Lock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Locker_Lock_Call) RunAndReturn(run func(context.Context, string, string, time.Duration) (bool, error)) *Locker_Lock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Refresh provides a mock function with given fields: ctx, key, owner, ttl
+func (_m *Locker) Refresh(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, owner, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, key, owner, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) bool); ok {
+		r0 = rf(ctx, key, owner, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, owner, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Locker_Refresh_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Refresh'
+type Locker_Refresh_Call struct {
+	*mock.Call
+}
+
+// Refresh is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - owner string
+//   - ttl time.Duration
+func (_e *Locker_Expecter) Refresh(ctx interface{}, key interface{}, owner interface{}, ttl interface{}) *Locker_Refresh_Call {
+	return &Locker_Refresh_Call{Call: _e.mock.On("Refresh", ctx, key, owner, ttl)}
+}
+
+func (_c *Locker_Refresh_Call) Run(run func(ctx context.Context, key string, owner string, ttl time.Duration)) *Locker_Refresh_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Locker_Refresh_Call) Return(_a0 bool, _a1 error) *Locker_Refresh_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Locker_Refresh_Call) RunAndReturn(run func(context.Context, string, string, time.Duration) (bool, error)) *Locker_Refresh_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unlock provides a mock function with given fields: ctx, key, owner
+func (_m *Locker) Unlock(ctx context.Context, key string, owner string) error {
+	ret := _m.Called(ctx, key, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, key, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Locker_Unlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unlock'
+type Locker_Unlock_Call struct {
+	*mock.Call
+}
+
+// Unlock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - owner string
+func (_e *Locker_Expecter) Unlock(ctx interface{}, key interface{}, owner interface{}) *Locker_Unlock_Call {
+	return &Locker_Unlock_Call{Call: _e.mock.On("Unlock", ctx, key, owner)}
+}
+
+func (_c *Locker_Unlock_Call) Run(run func(ctx context.Context, key string, owner string)) *Locker_Unlock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Locker_Unlock_Call) Return(_a0 error) *Locker_Unlock_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Locker_Unlock_Call) RunAndReturn(run func(context.Context, string, string) error) *Locker_Unlock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLocker creates a new instance of Locker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Locker {
+	mock := &Locker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
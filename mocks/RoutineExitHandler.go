@@ -0,0 +1,69 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package outismocks
+
+import (
+	outis "github.com/Brisanet/outis"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoutineExitHandler is an autogenerated mock type for the RoutineExitHandler type
+type RoutineExitHandler struct {
+	mock.Mock
+}
+
+type RoutineExitHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RoutineExitHandler) EXPECT() *RoutineExitHandler_Expecter {
+	return &RoutineExitHandler_Expecter{mock: &_m.Mock}
+}
+
+// OnRoutineExit provides a mock function with given fields: ctx, err
+func (_m *RoutineExitHandler) OnRoutineExit(ctx outis.Context, err error) {
+	_m.Called(ctx, err)
+}
+
+// RoutineExitHandler_OnRoutineExit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OnRoutineExit'
+type RoutineExitHandler_OnRoutineExit_Call struct {
+	*mock.Call
+}
+
+// OnRoutineExit is a helper method to define mock.On call
+//   - ctx outis.Context
+//   - err error
+func (_e *RoutineExitHandler_Expecter) OnRoutineExit(ctx interface{}, err interface{}) *RoutineExitHandler_OnRoutineExit_Call {
+	return &RoutineExitHandler_OnRoutineExit_Call{Call: _e.mock.On("OnRoutineExit", ctx, err)}
+}
+
+func (_c *RoutineExitHandler_OnRoutineExit_Call) Run(run func(ctx outis.Context, err error)) *RoutineExitHandler_OnRoutineExit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(outis.Context), args[1].(error))
+	})
+	return _c
+}
+
+func (_c *RoutineExitHandler_OnRoutineExit_Call) Return() *RoutineExitHandler_OnRoutineExit_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RoutineExitHandler_OnRoutineExit_Call) RunAndReturn(run func(outis.Context, error)) *RoutineExitHandler_OnRoutineExit_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewRoutineExitHandler creates a new instance of RoutineExitHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoutineExitHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoutineExitHandler {
+	mock := &RoutineExitHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
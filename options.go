@@ -1,6 +1,11 @@
 package outis
 
-import "time"
+import (
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 // Option defines the option type of a routine
 type Option func(*ContextImpl)
@@ -39,11 +44,90 @@ func WithMinutes(start, end uint) Option {
 	}
 }
 
+// WithWeekdays restricts the execution of the script to the days of the week
+func WithWeekdays(days ...time.Weekday) Option {
+	return func(ctx *ContextImpl) { ctx.period.weekdays = days }
+}
+
+// WithMonthDays restricts the execution of the script to the days of the month, between 1 and 31
+func WithMonthDays(days ...int) Option {
+	return func(ctx *ContextImpl) { ctx.period.monthDays = days }
+}
+
+// WithMonths restricts the execution of the script to the months
+func WithMonths(months ...time.Month) Option {
+	return func(ctx *ContextImpl) { ctx.period.months = months }
+}
+
+// WithHolidays defines the dates of the calendar in which the script is not executed,
+// the calendar can be loaded from an iCalendar file or from a list of dates with LoadCalendar
+func WithHolidays(calendar *Calendar) Option {
+	return func(ctx *ContextImpl) { ctx.period.holidays = calendar }
+}
+
 // WithInterval defines the interval at which the script will be executed
 func WithInterval(duration time.Duration) Option {
 	return func(ctx *ContextImpl) { ctx.Interval = duration }
 }
 
+// WithCron defines a cron expression that schedules the script execution, replacing the interval.
+// Accepts 5 fields (minute, hour, day of month, month and day of week), 6 fields with the
+// seconds at the beginning or the macros @yearly, @monthly, @weekly, @daily and @hourly
+func WithCron(expr string) Option {
+	return func(ctx *ContextImpl) { ctx.Cron = expr }
+}
+
+// WithLocation defines the time zone used to calculate the period and the schedule of a routine,
+// by default the location of the watcher is used
+func WithLocation(loc *time.Location) Option {
+	return func(ctx *ContextImpl) { ctx.Location = loc }
+}
+
+// WithTimeout defines the maximum duration of each script execution, the script receives a context
+// with deadline and, when it is exceeded, the execution is abandoned and a TimeoutError is reported
+func WithTimeout(timeout time.Duration) Option {
+	return func(ctx *ContextImpl) { ctx.timeout = timeout }
+}
+
+// WithRetryPolicy defines how the script execution is retried when it fails,
+// each attempt receives its own execution ID
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(ctx *ContextImpl) { ctx.retryPolicy = &policy }
+}
+
+// WithRestartPolicy defines how the loop of the routine is rebuilt when it finishes with an error,
+// a panic or, with RestartAlways, without error. Each restart is reported with an EventRestart
+func WithRestartPolicy(policy RestartPolicy) Option {
+	return func(ctx *ContextImpl) { ctx.restartPolicy = &policy }
+}
+
+// WithOverlapPolicy defines what happens when an execution is due while the previous one
+// is still in progress, by default the next execution is delayed until the previous one finishes
+func WithOverlapPolicy(policy OverlapPolicy) Option {
+	return func(ctx *ContextImpl) { ctx.overlap = policy }
+}
+
+// WithCatchUp defines what happens with the executions missed while the watcher was down,
+// detected on startup from the last successful execution kept in the history of the watcher.
+// Each catch-up execution receives the time of the missed slot as scheduled time.
+// With DistributedLock, the missed slots are executed by a single replica of the watcher
+func WithCatchUp(policy CatchUpPolicy) Option {
+	return func(ctx *ContextImpl) { ctx.catchUpPolicy = &policy }
+}
+
+// WithJitter defines a random delay added to each execution, spreading the executions of routines
+// and replicas with the same schedule. The scheduled time of the executions is not changed, so the delay
+// does not accumulate over time
+func WithJitter(jitter Jitter) Option {
+	return func(ctx *ContextImpl) { ctx.jitter = &jitter }
+}
+
+// WithStartOffset defines a fixed delay added to each execution, such as an offset of 15 minutes
+// in a routine scheduled every hour. The scheduled time of the executions is not changed
+func WithStartOffset(offset time.Duration) Option {
+	return func(ctx *ContextImpl) { ctx.startOffset = offset }
+}
+
 // WithNotUseLoop define that the routine will not enter a loop
 func WithNotUseLoop() Option {
 	return func(ctx *ContextImpl) { ctx.notUseLoop = true }
@@ -66,3 +150,69 @@ func Logger(logger ILogger) WatcherOption {
 func Impl(outis IOutis) WatcherOption {
 	return func(watch *Watch) { watch.outis = outis }
 }
+
+// Location defines the default time zone of the routines, by default the local time zone is used
+func Location(loc *time.Location) WatcherOption {
+	return func(watch *Watch) { watch.location = loc }
+}
+
+// ShutdownOnSignal defines that the watcher is shut down when one of the signals is received,
+// waiting for the executions in progress until the timeout. By default SIGINT and SIGTERM are used
+func ShutdownOnSignal(timeout time.Duration, signals ...os.Signal) WatcherOption {
+	return func(watch *Watch) { watch.signals = &shutdownSignals{timeout: timeout, signals: signals} }
+}
+
+// DistributedLock defines the lock acquired before each execution of a routine, using the routine
+// ID as key, so that a routine is executed by a single process at a time. The lease lasts for the ttl
+// and is renewed while the script runs, by default the ttl is one minute. After a scheduled execution
+// the lock is kept until the next slot of the schedule, so each slot is executed by a single replica
+func DistributedLock(locker Locker, ttl time.Duration) WatcherOption {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	return func(watch *Watch) { watch.lock = &lockOptions{locker: locker, ttl: ttl} }
+}
+
+// LeaderElection defines that only one replica of the watcher, identified by the watcher ID, is active
+// at a time. The routines of the other replicas stay paused until the lease of the leader expires.
+// By default the ttl is one minute
+func LeaderElection(locker Locker, ttl time.Duration) WatcherOption {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	return func(watch *Watch) { watch.leadership = newLeadership(locker, ttl) }
+}
+
+// TracerProvider defines the provider of the tracer used to create a span for each execution,
+// by default the global provider of OpenTelemetry is used
+func TracerProvider(provider trace.TracerProvider) WatcherOption {
+	return func(watch *Watch) { watch.tracerProvider = provider }
+}
+
+// History defines the store where every execution attempt is recorded, with its result, metadata,
+// indicators and histograms. The executions older than the retention are removed periodically,
+// when the retention is zero the executions are kept indefinitely
+func History(store ExecutionStore, retention time.Duration) WatcherOption {
+	return func(watch *Watch) { watch.history = &history{store: store, retention: retention} }
+}
+
+// AdminServer defines the address of the HTTP server of the admin API, which lists the routines
+// of the watcher and allows triggering, pausing, resuming and cancelling their executions.
+// The middlewares wrap the handler of the API in the given order, the first being the outermost,
+// such as AdminBasicAuth. Without an authentication middleware the address must not be exposed publicly
+func AdminServer(addr string, middlewares ...AdminMiddleware) WatcherOption {
+	return func(watch *Watch) {
+		watch.adminAddr = addr
+		watch.adminMiddlewares = middlewares
+	}
+}
+
+// Clock defines the source of time used to schedule, wait and measure the executions of the routines,
+// such as outistest.FakeClock to test the schedules without waiting. By default the system clock is used.
+// The clock also drives the renewal of the locks, the leader election and the history pruning, while
+// the expiration of the leases is kept by each Locker implementation
+func Clock(clock IClock) WatcherOption {
+	return func(watch *Watch) { watch.clock = clock }
+}
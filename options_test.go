@@ -0,0 +1,32 @@
+package outis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminServerMiddlewares(t *testing.T) {
+	var (
+		order      []string
+		middleware = func(name string) AdminMiddleware {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					order = append(order, name)
+					next.ServeHTTP(w, req)
+				})
+			}
+		}
+		watch = &Watch{registry: newRegistry()}
+	)
+
+	AdminServer("", middleware("first"), middleware("second"))(watch)
+
+	rec := httptest.NewRecorder()
+	watch.adminServerHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/routines", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"first", "second"}, order)
+}
@@ -1,6 +1,7 @@
 package outis
 
 import (
+	"errors"
 	"fmt"
 
 	"golang.org/x/sync/errgroup"
@@ -10,7 +11,9 @@ type server struct {
 	errGroup errgroup.Group
 }
 
-func newOutis() IOutis {
+// NewOutis creates the default implementation of the main interface,
+// which can be decorated by other implementations
+func NewOutis() IOutis {
 	return &server{
 		errGroup: errgroup.Group{},
 	}
@@ -46,7 +49,77 @@ func (s *server) After(ctx Context) error {
 
 // Event implements a business rule for event handling
 func (s *server) Event(ctx Context, event Event) {
-	if metric, ok := event.(EventMetric); ok {
-		ctx.LogDebug("Metrics", LogFields{"metrics": metric})
+	switch e := event.(type) {
+	case EventMetric:
+		ctx.LogDebug("Metrics", LogFields{"metrics": e})
+	case EventRetry:
+		ctx.LogWarn(fmt.Sprintf("script '%s' (rid: %s, id: %s) attempt %d failed", ctx.Name(), ctx.RoutineID(), e.ID, e.Attempt),
+			LogFields{"cause": e.Err.Error(), "retrying": e.Retrying, "delay": e.Delay.String()})
+	case EventRestart:
+		fields := LogFields{"restart": e.Restart, "restarting": e.Restarting, "delay": e.Delay.String()}
+		if e.Err != nil {
+			fields["cause"] = e.Err.Error()
+		}
+		ctx.LogWarn(fmt.Sprintf("script '%s' (rid: %s) finished, applying the restart policy", ctx.Name(), ctx.RoutineID()), fields)
 	}
 }
+
+// OnError implements a business rule for errors of script execution,
+// the panics are logged with the stack trace of the goroutine that panicked
+func (s *server) OnError(ctx Context, err error) {
+	logError(ctx, err)
+}
+
+// HandleError sends the error to the implementation when it implements ErrorHandler, otherwise
+// the error is logged. It is used by the watcher and by the implementations that wrap another one
+func HandleError(impl IOutis, ctx Context, err error) {
+	if handler, ok := impl.(ErrorHandler); ok {
+		handler.OnError(ctx, err)
+		return
+	}
+
+	logError(ctx, err)
+}
+
+func (watch *Watch) onError(ctx Context, err error) {
+	HandleError(watch.outis, ctx, err)
+}
+
+// logError logs the error, with the stack trace when it is a panic
+func logError(ctx Context, err error) {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		ctx.LogError(err, LogFields{"stack": string(panicErr.Stack)})
+		return
+	}
+
+	ctx.LogError(err)
+}
+
+// OnRoutineExit implements a business rule for the termination of a routine,
+// the error is nil when the routine was stopped or the watcher was shut down
+func (s *server) OnRoutineExit(ctx Context, err error) {
+	logRoutineExit(ctx, err)
+}
+
+// HandleRoutineExit notifies the implementation of the termination of the routine when it implements
+// RoutineExitHandler, otherwise the termination is logged. It is used by the watcher and by the
+// implementations that wrap another one
+func HandleRoutineExit(impl IOutis, ctx Context, err error) {
+	if handler, ok := impl.(RoutineExitHandler); ok {
+		handler.OnRoutineExit(ctx, err)
+		return
+	}
+
+	logRoutineExit(ctx, err)
+}
+
+// logRoutineExit logs the termination of the routine
+func logRoutineExit(ctx Context, err error) {
+	if err != nil {
+		ctx.LogErrorMsg(fmt.Sprintf("script '%s' (rid: %s) failed", ctx.Name(), ctx.RoutineID()), LogFields{"cause": err.Error()})
+		return
+	}
+
+	ctx.LogInfo(fmt.Sprintf("script '%s' (rid: %s) stopped", ctx.Name(), ctx.RoutineID()))
+}
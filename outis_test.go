@@ -0,0 +1,165 @@
+package outis_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+	"github.com/Brisanet/outis/outistest"
+)
+
+// minimalOutis implements only the required methods of IOutis, like the implementations
+// written before the optional interfaces were added
+type minimalOutis struct {
+	outis.IOutis
+}
+
+func newMinimalOutis() *minimalOutis {
+	return &minimalOutis{IOutis: outistest.NewRecorder(nil)}
+}
+
+// errorHandler records the errors received by OnError
+type errorHandler struct {
+	*minimalOutis
+	errs []error
+}
+
+func (h *errorHandler) OnError(_ outis.Context, err error) {
+	h.errs = append(h.errs, err)
+}
+
+func TestHandleError(t *testing.T) {
+	var (
+		ctx     = outistest.NewTestContext(t)
+		failure = errors.New("failure")
+	)
+
+	t.Run("without handler", func(t *testing.T) {
+		outis.HandleError(newMinimalOutis(), ctx, failure)
+		ctx.AssertLogged(t, outis.ErrorLevel, "failure")
+	})
+
+	t.Run("with handler", func(t *testing.T) {
+		handler := &errorHandler{minimalOutis: newMinimalOutis()}
+		outis.HandleError(handler, ctx, failure)
+		assert.Equal(t, []error{failure}, handler.errs)
+	})
+}
+
+func TestHandleErrorPanic(t *testing.T) {
+	var (
+		logs  = outistest.NewLogRecorder()
+		watch = outis.Watcher("watcher", "watcher", outis.Logger(logs), outis.Impl(newMinimalOutis()))
+	)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+
+	watch.Go(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithInterval(time.Hour),
+		outis.WithExecuteFirstTimeBeforeInterval(),
+		outis.WithScript(func(outis.Context) error { panic("boom") }),
+	)
+
+	// Sem o ErrorHandler, o panic é registrado no log com o stack trace
+	assert.Eventually(t, func() bool {
+		for _, entry := range logs.Entries() {
+			if entry.Level == outis.ErrorLevel && entry.Fields["stack"] != nil {
+				return strings.Contains(entry.Message, "boom")
+			}
+		}
+		return false
+	}, 5*time.Second, time.Millisecond, "the messages were:\n%s", logs)
+}
+
+func TestScheduledPanic(t *testing.T) {
+	var (
+		clock    = outistest.NewFakeClock(start)
+		recorder = outistest.NewRecorder(newMinimalOutis())
+		watch    = newClockWatcher(t, clock, outis.Impl(recorder))
+		runs     int
+	)
+
+	watch.Go(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithInterval(time.Minute),
+		outis.WithScript(func(outis.Context) error {
+			if runs++; runs <= 2 {
+				panic("boom")
+			}
+			return nil
+		}),
+	)
+
+	// Os panics das execuções agendadas não interrompem o agendamento da rotina
+	clock.BlockUntil(1)
+	clock.Advance(3 * time.Minute)
+	assert.Equal(t, 3, runs)
+
+	errs := recorder.Errors()
+	require.Len(t, errs, 2)
+	for _, err := range errs {
+		var panicErr *outis.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, outis.ID("routine"), panicErr.RoutineID)
+		assert.Equal(t, "boom", panicErr.Value)
+		assert.NotEmpty(t, panicErr.Stack)
+	}
+
+	snapshot, ok := watch.Routine("routine")
+	require.True(t, ok)
+	assert.Equal(t, outis.RoutineWaiting, snapshot.State)
+	assert.Empty(t, snapshot.ExitError)
+	assert.Zero(t, snapshot.ConsecutiveFailures)
+}
+
+// exitHandler records the errors received by OnRoutineExit
+type exitHandler struct {
+	*minimalOutis
+	errs []error
+}
+
+func (h *exitHandler) OnRoutineExit(_ outis.Context, err error) {
+	h.errs = append(h.errs, err)
+}
+
+func TestHandleRoutineExit(t *testing.T) {
+	var (
+		ctx     = outistest.NewTestContext(t)
+		failure = errors.New("failure")
+	)
+
+	t.Run("without handler", func(t *testing.T) {
+		outis.HandleRoutineExit(newMinimalOutis(), ctx, failure)
+		ctx.AssertLogged(t, outis.ErrorLevel, "script 'test' (rid: test) failed")
+
+		outis.HandleRoutineExit(newMinimalOutis(), ctx, nil)
+		ctx.AssertLogged(t, outis.InfoLevel, "script 'test' (rid: test) stopped")
+	})
+
+	t.Run("with handler", func(t *testing.T) {
+		handler := &exitHandler{minimalOutis: newMinimalOutis()}
+		outis.HandleRoutineExit(handler, ctx, failure)
+		assert.Equal(t, []error{failure}, handler.errs)
+	})
+
+	t.Run("watcher", func(t *testing.T) {
+		var (
+			handler = &exitHandler{minimalOutis: newMinimalOutis()}
+			watch   = outis.Watcher("watcher", "watcher", outis.Logger(outistest.NewLogRecorder()), outis.Impl(handler))
+		)
+
+		watch.Go(outis.WithID("routine"), outis.WithName("routine"), outis.WithNotUseLoop(), outis.WithScript(func(outis.Context) error { return failure }))
+		watch.Wait()
+
+		assert.Len(t, handler.errs, 1)
+		assert.ErrorIs(t, handler.errs[0], failure)
+	})
+}
@@ -0,0 +1,222 @@
+package outistest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Brisanet/outis"
+)
+
+var (
+	_ outis.IClock       = (*FakeClock)(nil)
+	_ outis.ClockTracker = (*FakeClock)(nil)
+)
+
+// FakeClock is a clock controlled by the test, used with outis.Clock to test the schedules
+// of the routines without waiting. The time only moves when Advance or Set is called,
+// firing the timers of the intervals, of the period sleeps and of the timeouts in order
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+	// running is the number of goroutines of the watchers running, the timers are only fired when it is zero
+	running int
+	changed chan struct{}
+}
+
+// NewFakeClock creates a fake clock stopped at the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+// Now returns the current time of the clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer creates a timer that fires when the clock reaches the current time plus the duration
+func (c *FakeClock) NewTimer(d time.Duration) outis.ITimer {
+	return c.newTimer(d, false)
+}
+
+// NewTrackedTimer creates a timer waited by a goroutine of a watcher, which is marked as running when
+// the timer fires. It is used by the watcher, see outis.ClockTracker
+func (c *FakeClock) NewTrackedTimer(d time.Duration) outis.ITimer {
+	return c.newTimer(d, true)
+}
+
+func (c *FakeClock) newTimer(d time.Duration, tracked bool) outis.ITimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &fakeTimer{clock: c, deadline: c.now.Add(d), tracked: tracked, c: make(chan time.Time, 1)}
+	if d <= 0 {
+		c.fire(timer)
+		return timer
+	}
+
+	c.timers = append(c.timers, timer)
+	c.notify()
+
+	return timer
+}
+
+// Running marks a goroutine of a watcher as running, see outis.ClockTracker
+func (c *FakeClock) Running() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running++
+	c.notify()
+}
+
+// Waiting marks a goroutine of a watcher as waiting, see outis.ClockTracker
+func (c *FakeClock) Waiting() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running--
+	c.notify()
+}
+
+// Advance moves the clock forward by the duration, firing in chronological order the timers
+// that expire until the new time. Each timer is fired with the clock at its deadline
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to the time, firing the timers that expire until then. The clock never
+// moves backwards. After firing a timer Set waits for the goroutines of the watchers using the
+// clock to wait again, so the executions started by the timer finish and the timers of the
+// routines are armed again before the clock moves. This way moving the clock over several
+// intervals fires every tick of the routine, however long the script takes. Since the scripts
+// take no time of the clock, a script started by Set must not block until a later time of the
+// clock, such as its timeout, the script is then executed with Execute instead
+func (c *FakeClock) Set(t time.Time) {
+	for fired := false; ; fired = true {
+		if fired {
+			c.settle()
+		}
+
+		c.mu.Lock()
+		sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].deadline.Before(c.timers[j].deadline) })
+
+		if len(c.timers) == 0 || c.timers[0].deadline.After(t) {
+			if t.After(c.now) {
+				c.now = t
+			}
+			c.notify()
+			c.mu.Unlock()
+			return
+		}
+
+		timer := c.timers[0]
+		c.timers = c.timers[1:]
+		if timer.deadline.After(c.now) {
+			c.now = timer.deadline
+		}
+		c.fire(timer)
+		c.notify()
+		c.mu.Unlock()
+	}
+}
+
+// settle waits until no goroutine of the watchers is running
+func (c *FakeClock) settle() {
+	for {
+		c.mu.Lock()
+		running, changed := c.running, c.changed
+		c.mu.Unlock()
+
+		if running <= 0 {
+			return
+		}
+		<-changed
+	}
+}
+
+// fire sends the current time to the timer, marking the goroutine waiting for a tracked timer
+// as running. It must be called with the lock held
+func (c *FakeClock) fire(timer *fakeTimer) {
+	timer.c <- c.now
+	if timer.tracked {
+		c.running++
+	}
+}
+
+// Timers returns the number of timers waiting to be fired
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// BlockUntil blocks until at least n timers are waiting to be fired, which means the routines
+// reached the point where they wait for the clock. It must be called before Advance, since
+// the routines create their timers asynchronously
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mu.Lock()
+		count, changed := len(c.timers), c.changed
+		c.mu.Unlock()
+
+		if count >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+// notify wakes up the callers of BlockUntil and Set, it must be called with the lock held
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// stop removes the timer from the clock, returning whether it was waiting to be fired. When a tracked
+// timer fired and its time was not received, the time is discarded and its goroutine is marked as waiting
+func (c *FakeClock) stop(timer *fakeTimer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, t := range c.timers {
+		if t == timer {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.notify()
+			return true
+		}
+	}
+
+	if timer.tracked {
+		select {
+		case <-timer.c:
+			c.running--
+			c.notify()
+		default:
+		}
+	}
+
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	tracked  bool
+	c        chan time.Time
+}
+
+// C returns the channel where the time is sent when the timer fires
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop prevents the timer from firing, returning false when it already fired or was stopped
+func (t *fakeTimer) Stop() bool {
+	return t.clock.stop(t)
+}
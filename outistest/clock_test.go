@@ -0,0 +1,258 @@
+package outistest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+)
+
+var start = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+func TestFakeClockTimers(t *testing.T) {
+	clock := NewFakeClock(start)
+
+	var (
+		late  = clock.NewTimer(2 * time.Minute)
+		early = clock.NewTimer(time.Minute)
+		never = clock.NewTimer(time.Hour)
+	)
+	assert.Equal(t, 3, clock.Timers())
+
+	assert.True(t, never.Stop())
+	assert.False(t, never.Stop(), "a stopped timer must not be stopped again")
+
+	clock.Advance(90 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-early.C(), "the timer fires with the clock at its deadline")
+	assert.Equal(t, start.Add(90*time.Second), clock.Now())
+	assert.Equal(t, 1, clock.Timers())
+	assert.False(t, early.Stop(), "a fired timer must not be stopped")
+
+	clock.Set(start)
+	assert.Equal(t, start.Add(90*time.Second), clock.Now(), "the clock must not move backwards")
+
+	clock.Set(start.Add(time.Hour))
+	assert.Equal(t, start.Add(2*time.Minute), <-late.C())
+	assert.Equal(t, start.Add(time.Hour), clock.Now())
+	assert.Zero(t, clock.Timers())
+
+	select {
+	case <-never.C():
+		t.Fatal("a stopped timer must not fire")
+	default:
+	}
+
+	immediate := clock.NewTimer(0)
+	assert.Equal(t, start.Add(time.Hour), <-immediate.C())
+	assert.Zero(t, clock.Timers())
+}
+
+func TestFakeClockFiresRearmedTimers(t *testing.T) {
+	var (
+		clock = NewFakeClock(start)
+		ticks []time.Time
+		done  = make(chan struct{})
+	)
+
+	// A goroutine segue o protocolo do watcher, marcando-se como aguardando antes de cada timer
+	clock.Running()
+	go func() {
+		defer close(done)
+		defer clock.Waiting()
+
+		for len(ticks) < 60 {
+			timer := clock.NewTrackedTimer(time.Minute)
+			clock.Waiting()
+			ticks = append(ticks, <-timer.C())
+
+			// Um processamento demorado entre os timers não perde os disparos
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	<-done
+
+	require.Len(t, ticks, 60)
+	for i, tick := range ticks {
+		assert.Equal(t, start.Add(time.Duration(i+1)*time.Minute), tick)
+	}
+}
+
+func TestFakeClockStopTrackedTimer(t *testing.T) {
+	clock := NewFakeClock(start)
+
+	clock.Running()
+	timer := clock.NewTrackedTimer(0)
+	clock.Waiting()
+
+	// O disparo não recebido é descartado ao parar o timer, então o relógio não aguarda a goroutine
+	assert.False(t, timer.Stop())
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), clock.Now())
+
+	select {
+	case <-timer.C():
+		t.Fatal("the time of a stopped timer must be discarded")
+	default:
+	}
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	clock := NewFakeClock(start)
+
+	blocked := make(chan struct{})
+	go func() {
+		clock.BlockUntil(2)
+		close(blocked)
+	}()
+
+	clock.NewTimer(time.Minute)
+	select {
+	case <-blocked:
+		t.Fatal("BlockUntil must wait for two timers")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.NewTimer(time.Minute)
+	<-blocked
+}
+
+func TestFakeClockRoutine(t *testing.T) {
+	var (
+		clock = NewFakeClock(start)
+		watch = outis.Watcher("watcher", "watcher", outis.Logger(NewLogRecorder()), outis.Clock(clock))
+
+		mu        sync.Mutex
+		scheduled []time.Time
+	)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+
+	watch.Go(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithInterval(time.Minute),
+		outis.WithScript(func(ctx outis.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			scheduled = append(scheduled, ctx.ScheduledAt())
+			return nil
+		}),
+	)
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, scheduled, 60)
+	for i, at := range scheduled {
+		assert.Equal(t, start.Add(time.Duration(i+1)*time.Minute), at)
+	}
+}
+
+func TestFakeClockSlowScript(t *testing.T) {
+	var (
+		clock = NewFakeClock(start)
+		watch = outis.Watcher("watcher", "watcher", outis.Logger(NewLogRecorder()), outis.Clock(clock))
+
+		mu        sync.Mutex
+		scheduled []time.Time
+	)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+
+	watch.Go(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithInterval(time.Minute),
+		outis.WithTimeout(30*time.Second),
+		outis.WithScript(func(ctx outis.Context) error {
+			time.Sleep(60 * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			scheduled = append(scheduled, ctx.ScheduledAt())
+			return nil
+		}),
+	)
+
+	// Advance aguarda cada execução terminar antes de mover o relógio, então o script
+	// demorado não perde os disparos nem excede o timeout medido pelo relógio
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Minute)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, scheduled, 5)
+	for i, at := range scheduled {
+		assert.Equal(t, start.Add(time.Duration(i+1)*time.Minute), at)
+	}
+}
+
+func TestFakeClockTimersWithoutRearm(t *testing.T) {
+	var (
+		clock = NewFakeClock(start)
+		wg    sync.WaitGroup
+	)
+
+	// Os timers que não são recriados após o disparo não atrasam o avanço do relógio
+	for i := 1; i <= 100; i++ {
+		timer := clock.NewTrackedTimer(time.Duration(i) * time.Second)
+
+		wg.Add(1)
+		clock.Running()
+		go func() {
+			defer wg.Done()
+			defer clock.Waiting()
+
+			clock.Waiting()
+			<-timer.C()
+		}()
+	}
+
+	began := time.Now()
+	clock.Advance(time.Hour)
+	assert.Less(t, time.Since(began), time.Second)
+
+	wg.Wait()
+	assert.Zero(t, clock.Timers())
+}
+
+func TestFakeClockTimeout(t *testing.T) {
+	var (
+		clock = NewFakeClock(start)
+		watch = outis.Watcher("watcher", "watcher", outis.Logger(NewLogRecorder()), outis.Clock(clock))
+	)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+
+	ctx, err := watch.NewContext(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithTimeout(time.Minute),
+		outis.WithScript(func(ctx outis.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	require.NoError(t, err)
+
+	result := make(chan error, 1)
+	go func() { result <- ctx.Execute() }()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	select {
+	case err := <-result:
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the timeout did not cancel the script")
+	}
+}
@@ -0,0 +1,177 @@
+package outistest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Brisanet/outis"
+)
+
+// TestContext is the context of a single execution of a routine, used to unit test the scripts.
+// It captures the logs, the metadata, the indicators and the histograms of the execution, and the
+// calls to the main interface when the routine is executed with Execute
+type TestContext struct {
+	*outis.ContextImpl
+
+	logs  *LogRecorder
+	outis *Recorder
+	// executed são as métricas da última tentativa executada por Execute, pois
+	// as medidas do contexto são reiniciadas ao final de cada tentativa
+	executed *outis.EventMetric
+}
+
+// NewTestContext creates the context of an execution with the options of the routine. By default the
+// routine ID and name are "test", so only the options relevant to the script are needed, such as
+// WithScript to use Execute or WithTimeout. The script can also be called directly with the context.
+// The watcher of the context is shut down when the test finishes, and the test fails immediately
+// when the options are invalid
+//
+//	ctx := outistest.NewTestContext(t)
+//	err := script(ctx)
+//	ctx.AssertIndicator(t, "processed", 10)
+func NewTestContext(t testing.TB, opts ...outis.Option) *TestContext {
+	t.Helper()
+
+	var (
+		logs     = NewLogRecorder()
+		recorder = NewRecorder(nil)
+		watch    = outis.Watcher("test", "test", outis.Logger(logs), outis.Impl(recorder))
+		defaults = []outis.Option{
+			outis.WithID("test"),
+			outis.WithName("test"),
+			outis.WithScript(func(outis.Context) error { return nil }),
+		}
+	)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+
+	ctx, err := watch.NewContext(append(defaults, opts...)...)
+	if err != nil {
+		t.Fatalf("outistest: invalid routine options: %v", err)
+	}
+
+	return &TestContext{ContextImpl: ctx, logs: logs, outis: recorder}
+}
+
+// Execute executes the script of the routine as a scheduled execution, with the hooks of the main
+// interface, the retry policy and the timeout. After it, the metadata, the indicators and the
+// histograms of the context are the ones recorded by the last attempt
+func (ctx *TestContext) Execute() error {
+	err := ctx.ContextImpl.Execute()
+
+	if metrics := ctx.outis.Metrics(); len(metrics) > 0 {
+		ctx.executed = &metrics[len(metrics)-1]
+	}
+
+	return err
+}
+
+// Metadata returns the metadata added to the execution
+func (ctx *TestContext) Metadata() outis.Metadata {
+	if ctx.executed != nil {
+		return ctx.executed.Metadata
+	}
+	return ctx.ContextImpl.Metadata()
+}
+
+// Indicators returns the indicators created in the execution
+func (ctx *TestContext) Indicators() []*outis.Indicator {
+	if ctx.executed != nil {
+		return ctx.executed.Indicators
+	}
+	return ctx.ContextImpl.Indicators()
+}
+
+// Histograms returns copies of the histograms created in the execution
+func (ctx *TestContext) Histograms() []*outis.Histogram {
+	if ctx.executed != nil {
+		return ctx.executed.Histograms
+	}
+	return ctx.ContextImpl.Histograms()
+}
+
+// Logs returns the recorder of the messages logged in the execution
+func (ctx *TestContext) Logs() *LogRecorder {
+	return ctx.logs
+}
+
+// Outis returns the recorder of the calls to the main interface
+func (ctx *TestContext) Outis() *Recorder {
+	return ctx.outis
+}
+
+// Indicator returns the value of the indicator created in the execution
+func (ctx *TestContext) Indicator(key string) (float64, bool) {
+	for _, indicator := range ctx.Indicators() {
+		if indicator.GetKey() == key {
+			return indicator.GetValue(), true
+		}
+	}
+
+	return 0, false
+}
+
+// Histogram returns a copy of the histogram created in the execution
+func (ctx *TestContext) Histogram(key string) (*outis.Histogram, bool) {
+	for _, histogram := range ctx.Histograms() {
+		if histogram.GetKey() == key {
+			return histogram, true
+		}
+	}
+
+	return nil, false
+}
+
+// AssertLogged fails the test when no message of the level containing the text was logged
+func (ctx *TestContext) AssertLogged(t testing.TB, level outis.LogLevel, text string) {
+	t.Helper()
+
+	if !ctx.logs.Logged(level, text) {
+		t.Errorf("no %s message containing %q was logged, the messages were:\n%s", level, text, ctx.logs)
+	}
+}
+
+// AssertMetadata fails the test when the metadata of the key is not equal to the value
+func (ctx *TestContext) AssertMetadata(t testing.TB, key string, value interface{}) {
+	t.Helper()
+
+	actual, ok := ctx.Metadata()[key]
+	if !ok {
+		t.Errorf("the metadata %q was not added", key)
+		return
+	}
+
+	if !reflect.DeepEqual(actual, value) {
+		t.Errorf("the metadata %q is %v (%T), expected %v (%T)", key, actual, actual, value, value)
+	}
+}
+
+// AssertIndicator fails the test when the indicator was not created or its value is not the given value
+func (ctx *TestContext) AssertIndicator(t testing.TB, key string, value float64) {
+	t.Helper()
+
+	actual, ok := ctx.Indicator(key)
+	if !ok {
+		t.Errorf("the indicator %q was not created", key)
+		return
+	}
+
+	if actual != value {
+		t.Errorf("the indicator %q is %v, expected %v", key, actual, value)
+	}
+}
+
+// AssertHistogram fails the test when the histogram was not created or the amount of values recorded is not count
+func (ctx *TestContext) AssertHistogram(t testing.TB, key string, count uint64) {
+	t.Helper()
+
+	histogram, ok := ctx.Histogram(key)
+	if !ok {
+		t.Errorf("the histogram %q was not created", key)
+		return
+	}
+
+	if actual := histogram.Count(); actual != count {
+		t.Errorf("the histogram %q recorded %d values, expected %d", key, actual, count)
+	}
+}
@@ -0,0 +1,152 @@
+package outistest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+)
+
+func script(ctx outis.Context) error {
+	ctx.LogInfo("clientes processados")
+	ctx.AddSingleMetadata("client_id", 42)
+	ctx.NewIndicator("processed").Add(10)
+
+	latency := ctx.NewHistogram("latency")
+	for i := 0; i < 10; i++ {
+		latency.Add(float64(i))
+	}
+
+	return nil
+}
+
+func TestTestContextScript(t *testing.T) {
+	ctx := NewTestContext(t)
+
+	require.NoError(t, script(ctx))
+
+	ctx.AssertLogged(t, outis.InfoLevel, "clientes processados")
+	ctx.AssertMetadata(t, "client_id", 42)
+	ctx.AssertIndicator(t, "processed", 10)
+	ctx.AssertHistogram(t, "latency", 10)
+	assert.Equal(t, outis.ID("test"), ctx.RoutineID())
+	assert.Empty(t, ctx.Outis().Calls(), "calling the script directly must not call the hooks")
+}
+
+func TestTestContextExecute(t *testing.T) {
+	ctx := NewTestContext(t, outis.WithScript(script))
+
+	require.NoError(t, ctx.Execute())
+
+	ctx.AssertLogged(t, outis.InfoLevel, "clientes processados")
+	ctx.AssertMetadata(t, "client_id", 42)
+	ctx.AssertIndicator(t, "processed", 10)
+	ctx.AssertHistogram(t, "latency", 10)
+	assert.Equal(t, []string{HookBefore, HookAfter, HookEvent}, ctx.Outis().Hooks())
+	assert.Len(t, ctx.Outis().Metrics(), 1)
+}
+
+func TestTestContextExecuteError(t *testing.T) {
+	var (
+		failure = errors.New("failure")
+		ctx     = NewTestContext(t, outis.WithScript(func(outis.Context) error { return failure }))
+	)
+
+	assert.ErrorIs(t, ctx.Execute(), failure)
+	assert.Equal(t, []error{failure}, ctx.Outis().Errors())
+}
+
+func TestTestContextCancel(t *testing.T) {
+	ctx := NewTestContext(t, outis.WithScript(func(ctx outis.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	ctx.Cancel()
+	assert.ErrorIs(t, ctx.Execute(), context.Canceled)
+}
+
+// fatalTB records the failure of the test, stopping the goroutine like testing.T
+type fatalTB struct {
+	testing.TB
+	message string
+}
+
+func (t *fatalTB) Fatalf(format string, args ...interface{}) {
+	t.message = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+func TestTestContextInvalidOptions(t *testing.T) {
+	var (
+		tb   = &fatalTB{TB: t}
+		done = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+		NewTestContext(tb, outis.WithName(""))
+		t.Error("NewTestContext must stop the test")
+	}()
+	<-done
+
+	assert.Equal(t, "outistest: invalid routine options: the routine name is required", tb.message)
+}
+
+func TestTestContextShutdown(t *testing.T) {
+	var ctx *TestContext
+	t.Run("test", func(t *testing.T) {
+		ctx = NewTestContext(t)
+		assert.NoError(t, ctx.Err())
+	})
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watcher of the context must be shut down at the end of the test")
+	}
+}
+
+// errorTB records the errors of the assertions, without failing the test
+type errorTB struct {
+	testing.TB
+	errors []string
+}
+
+func (t *errorTB) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestTestContextAssertionsFail(t *testing.T) {
+	ctx := NewTestContext(t)
+	require.NoError(t, script(ctx))
+
+	for _, test := range []struct {
+		name      string
+		assertion func(t testing.TB)
+		message   string
+	}{
+		{name: "logged", assertion: func(t testing.TB) { ctx.AssertLogged(t, outis.ErrorLevel, "clientes") }, message: `no ErrorLevel message containing "clientes" was logged`},
+		{name: "missing metadata", assertion: func(t testing.TB) { ctx.AssertMetadata(t, "other", 42) }, message: `the metadata "other" was not added`},
+		{name: "metadata type", assertion: func(t testing.TB) { ctx.AssertMetadata(t, "client_id", int64(42)) }, message: `the metadata "client_id" is 42 (int), expected 42 (int64)`},
+		{name: "missing indicator", assertion: func(t testing.TB) { ctx.AssertIndicator(t, "other", 10) }, message: `the indicator "other" was not created`},
+		{name: "indicator value", assertion: func(t testing.TB) { ctx.AssertIndicator(t, "processed", 11) }, message: `the indicator "processed" is 10, expected 11`},
+		{name: "missing histogram", assertion: func(t testing.TB) { ctx.AssertHistogram(t, "other", 10) }, message: `the histogram "other" was not created`},
+		{name: "histogram count", assertion: func(t testing.TB) { ctx.AssertHistogram(t, "latency", 9) }, message: `the histogram "latency" recorded 10 values, expected 9`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			tb := &errorTB{TB: t}
+			test.assertion(tb)
+
+			require.Len(t, tb.errors, 1)
+			assert.Contains(t, tb.errors[0], test.message)
+		})
+	}
+}
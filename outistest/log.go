@@ -0,0 +1,138 @@
+package outistest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Brisanet/outis"
+)
+
+// LogEntry is a message captured by the LogRecorder, with the fields of the logger and of the message
+type LogEntry struct {
+	Level   outis.LogLevel
+	Message string
+	Fields  outis.LogFields
+}
+
+// LogRecorder implements outis.ILogger keeping the messages in memory, to be used with
+// outis.Logger when testing what the routines log. The loggers created with AddField
+// and AddFields record in the same recorder
+type LogRecorder struct {
+	entries *logEntries
+	fields  outis.LogFields
+}
+
+type logEntries struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+var _ outis.ILogger = (*LogRecorder)(nil)
+
+// NewLogRecorder creates an empty log recorder
+func NewLogRecorder() *LogRecorder {
+	return &LogRecorder{entries: &logEntries{}, fields: outis.LogFields{}}
+}
+
+// Entries returns the messages recorded, in the order they were logged
+func (l *LogRecorder) Entries() []LogEntry {
+	l.entries.mu.Lock()
+	defer l.entries.mu.Unlock()
+
+	return append([]LogEntry(nil), l.entries.entries...)
+}
+
+// Logged returns whether a message of the level containing the text was recorded
+func (l *LogRecorder) Logged(level outis.LogLevel, text string) bool {
+	for _, entry := range l.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Level returns the debug level, so every message is recorded
+func (l *LogRecorder) Level() outis.LogLevel {
+	return outis.DebugLevel
+}
+
+// Info records a message of level Info
+func (l *LogRecorder) Info(msg string, fields ...outis.LogFields) {
+	l.record(outis.InfoLevel, msg, fields)
+}
+
+// Error records the error message with level Error, keeping the error in the cause field
+func (l *LogRecorder) Error(err error, fields ...outis.LogFields) {
+	l.record(outis.ErrorLevel, err.Error(), append(fields, outis.LogFields{"cause": err}))
+}
+
+// ErrorMsg records a message of level Error
+func (l *LogRecorder) ErrorMsg(msg string, fields ...outis.LogFields) {
+	l.record(outis.ErrorLevel, msg, fields)
+}
+
+// Fatal records a message of level Fatal, without exiting the process
+func (l *LogRecorder) Fatal(msg string, fields ...outis.LogFields) {
+	l.record(outis.FatalLevel, msg, fields)
+}
+
+// Panic records a message of level Panic and panics, like the default logger
+func (l *LogRecorder) Panic(msg string, fields ...outis.LogFields) {
+	l.record(outis.PanicLevel, msg, fields)
+	panic(msg)
+}
+
+// Debug records a message of level Debug
+func (l *LogRecorder) Debug(msg string, fields ...outis.LogFields) {
+	l.record(outis.DebugLevel, msg, fields)
+}
+
+// Warn records a message of level Warn
+func (l *LogRecorder) Warn(msg string, fields ...outis.LogFields) {
+	l.record(outis.WarnLevel, msg, fields)
+}
+
+// AddFields returns a logger that adds the fields to the messages
+func (l *LogRecorder) AddFields(fields ...outis.LogFields) outis.ILogger {
+	return &LogRecorder{entries: l.entries, fields: l.merge(fields)}
+}
+
+// AddField returns a logger that adds the field to the messages
+func (l *LogRecorder) AddField(key string, value interface{}) outis.ILogger {
+	return l.AddFields(outis.LogFields{key: value})
+}
+
+// String returns the recorded messages, one per line, useful in the messages of failed tests
+func (l *LogRecorder) String() string {
+	var builder strings.Builder
+	for _, entry := range l.Entries() {
+		fmt.Fprintf(&builder, "%s %s %v\n", entry.Level, entry.Message, entry.Fields)
+	}
+
+	return builder.String()
+}
+
+func (l *LogRecorder) record(level outis.LogLevel, msg string, fields []outis.LogFields) {
+	l.entries.mu.Lock()
+	defer l.entries.mu.Unlock()
+
+	l.entries.entries = append(l.entries.entries, LogEntry{Level: level, Message: msg, Fields: l.merge(fields)})
+}
+
+// merge returns the fields of the logger with the given fields
+func (l *LogRecorder) merge(fields []outis.LogFields) outis.LogFields {
+	merged := make(outis.LogFields, len(l.fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for _, f := range fields {
+		for key, value := range f {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,55 @@
+package outistest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Brisanet/outis"
+)
+
+func TestLogRecorder(t *testing.T) {
+	var (
+		logs    = NewLogRecorder()
+		failure = errors.New("failure")
+	)
+
+	logs.Info("info", outis.LogFields{"key": "value"})
+	logs.Warn("warn")
+	logs.Debug("debug")
+	logs.ErrorMsg("error message")
+	logs.Error(failure)
+	logs.Fatal("fatal")
+	assert.PanicsWithValue(t, "panic", func() { logs.Panic("panic") })
+
+	assert.Equal(t, []LogEntry{
+		{Level: outis.InfoLevel, Message: "info", Fields: outis.LogFields{"key": "value"}},
+		{Level: outis.WarnLevel, Message: "warn", Fields: outis.LogFields{}},
+		{Level: outis.DebugLevel, Message: "debug", Fields: outis.LogFields{}},
+		{Level: outis.ErrorLevel, Message: "error message", Fields: outis.LogFields{}},
+		{Level: outis.ErrorLevel, Message: "failure", Fields: outis.LogFields{"cause": failure}},
+		{Level: outis.FatalLevel, Message: "fatal", Fields: outis.LogFields{}},
+		{Level: outis.PanicLevel, Message: "panic", Fields: outis.LogFields{}},
+	}, logs.Entries())
+
+	assert.True(t, logs.Logged(outis.ErrorLevel, "fail"))
+	assert.False(t, logs.Logged(outis.InfoLevel, "fail"))
+	assert.Equal(t, outis.DebugLevel, logs.Level())
+	assert.Contains(t, logs.String(), "InfoLevel info map[key:value]\n")
+}
+
+func TestLogRecorderFields(t *testing.T) {
+	var (
+		logs    = NewLogRecorder()
+		routine = logs.AddField("routine", "sync").AddFields(outis.LogFields{"attempt": 1})
+	)
+
+	routine.Info("started", outis.LogFields{"attempt": 2})
+	logs.Info("watcher")
+
+	entries := logs.Entries()
+	assert.Len(t, entries, 2, "the derived loggers must record in the same recorder")
+	assert.Equal(t, outis.LogFields{"routine": "sync", "attempt": 2}, entries[0].Fields)
+	assert.Equal(t, outis.LogFields{}, entries[1].Fields, "the fields of a derived logger must not change the original one")
+}
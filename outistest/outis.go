@@ -0,0 +1,160 @@
+package outistest
+
+import (
+	"sync"
+
+	"github.com/Brisanet/outis"
+)
+
+// Hook names recorded by the Recorder
+const (
+	HookInit          = "Init"
+	HookBefore        = "Before"
+	HookAfter         = "After"
+	HookEvent         = "Event"
+	HookOnError       = "OnError"
+	HookOnRoutineExit = "OnRoutineExit"
+)
+
+// Call is a call to a hook of the main interface recorded by the Recorder
+type Call struct {
+	Hook    string
+	Context outis.Context
+	Event   outis.Event
+	Err     error
+}
+
+// Recorder implements outis.IOutis recording the calls to the hooks, to be used with outis.Impl
+// when testing the events and errors of the routines. The calls are delegated to the wrapped implementation
+type Recorder struct {
+	impl outis.IOutis
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var (
+	_ outis.IOutis             = (*Recorder)(nil)
+	_ outis.ErrorHandler       = (*Recorder)(nil)
+	_ outis.RoutineExitHandler = (*Recorder)(nil)
+)
+
+// NewRecorder creates a recorder wrapping the implementation, by default outis.NewOutis
+func NewRecorder(impl outis.IOutis) *Recorder {
+	if impl == nil {
+		impl = outis.NewOutis()
+	}
+
+	return &Recorder{impl: impl}
+}
+
+// Go delegates the function to the wrapped implementation
+func (r *Recorder) Go(fn func() error) {
+	r.impl.Go(fn)
+}
+
+// Wait delegates to the wrapped implementation
+func (r *Recorder) Wait() error {
+	return r.impl.Wait()
+}
+
+// Init records the initialization of the routine
+func (r *Recorder) Init(ctx outis.Context) error {
+	err := r.impl.Init(ctx)
+	r.record(Call{Hook: HookInit, Context: ctx, Err: err})
+	return err
+}
+
+// Before records the start of the execution
+func (r *Recorder) Before(ctx outis.Context) error {
+	err := r.impl.Before(ctx)
+	r.record(Call{Hook: HookBefore, Context: ctx, Err: err})
+	return err
+}
+
+// After records the end of the execution
+func (r *Recorder) After(ctx outis.Context) error {
+	err := r.impl.After(ctx)
+	r.record(Call{Hook: HookAfter, Context: ctx, Err: err})
+	return err
+}
+
+// Event records the event
+func (r *Recorder) Event(ctx outis.Context, event outis.Event) {
+	r.impl.Event(ctx, event)
+	r.record(Call{Hook: HookEvent, Context: ctx, Event: event})
+}
+
+// OnError records the error, delegating it to the wrapped implementation with outis.HandleError
+func (r *Recorder) OnError(ctx outis.Context, err error) {
+	outis.HandleError(r.impl, ctx, err)
+	r.record(Call{Hook: HookOnError, Context: ctx, Err: err})
+}
+
+// OnRoutineExit records the termination of the routine, notifying the wrapped implementation with outis.HandleRoutineExit
+func (r *Recorder) OnRoutineExit(ctx outis.Context, err error) {
+	outis.HandleRoutineExit(r.impl, ctx, err)
+	r.record(Call{Hook: HookOnRoutineExit, Context: ctx, Err: err})
+}
+
+// Calls returns the recorded calls, in the order they were made
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call(nil), r.calls...)
+}
+
+// Hooks returns the names of the hooks called, in the order they were called
+func (r *Recorder) Hooks() []string {
+	calls := r.Calls()
+	hooks := make([]string, 0, len(calls))
+	for _, call := range calls {
+		hooks = append(hooks, call.Hook)
+	}
+
+	return hooks
+}
+
+// Events returns the events received
+func (r *Recorder) Events() []outis.Event {
+	events := make([]outis.Event, 0)
+	for _, call := range r.Calls() {
+		if call.Hook == HookEvent {
+			events = append(events, call.Event)
+		}
+	}
+
+	return events
+}
+
+// Metrics returns the metric events received, one per execution attempt
+func (r *Recorder) Metrics() []outis.EventMetric {
+	metrics := make([]outis.EventMetric, 0)
+	for _, event := range r.Events() {
+		if metric, ok := event.(outis.EventMetric); ok {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics
+}
+
+// Errors returns the errors received by OnError
+func (r *Recorder) Errors() []error {
+	errs := make([]error, 0)
+	for _, call := range r.Calls() {
+		if call.Hook == HookOnError {
+			errs = append(errs, call.Err)
+		}
+	}
+
+	return errs
+}
+
+func (r *Recorder) record(call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, call)
+}
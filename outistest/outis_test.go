@@ -0,0 +1,58 @@
+package outistest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+)
+
+func TestRecorder(t *testing.T) {
+	var (
+		recorder = NewRecorder(nil)
+		ctx      = NewTestContext(t)
+		failure  = errors.New("failure")
+		event    = outis.EventMetric{ID: "execution"}
+	)
+
+	require.NoError(t, recorder.Init(ctx))
+	require.NoError(t, recorder.Before(ctx))
+	recorder.Event(ctx, event)
+	require.NoError(t, recorder.After(ctx))
+	recorder.OnError(ctx, failure)
+	recorder.OnRoutineExit(ctx, nil)
+
+	assert.Equal(t, []string{HookInit, HookBefore, HookEvent, HookAfter, HookOnError, HookOnRoutineExit}, recorder.Hooks())
+	assert.Equal(t, []outis.Event{event}, recorder.Events())
+	assert.Equal(t, []outis.EventMetric{event}, recorder.Metrics())
+	assert.Equal(t, []error{failure}, recorder.Errors())
+
+	calls := recorder.Calls()
+	assert.Equal(t, ctx, calls[0].Context)
+	assert.Equal(t, failure, calls[4].Err)
+
+	recorder.Go(func() error { return failure })
+	assert.Equal(t, failure, recorder.Wait())
+}
+
+func TestRecorderWithWatcher(t *testing.T) {
+	var (
+		recorder = NewRecorder(nil)
+		watch    = outis.Watcher("watcher", "watcher", outis.Logger(NewLogRecorder()), outis.Impl(recorder))
+	)
+
+	watch.Go(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithNotUseLoop(),
+		outis.WithScript(func(outis.Context) error { return nil }),
+	)
+	watch.Wait()
+
+	assert.Equal(t, []string{HookInit, HookBefore, HookAfter, HookEvent, HookOnRoutineExit}, recorder.Hooks())
+	require.Len(t, recorder.Metrics(), 1)
+	assert.Equal(t, "routine", recorder.Metrics()[0].Routine.ID)
+}
@@ -0,0 +1,14 @@
+// Package outistest provides utilities for testing the routines of the outis lib.
+package outistest
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// NewTracerProvider creates a tracer provider that keeps the finished spans in memory,
+// to be used with outis.TracerProvider when testing the traces of the routines
+func NewTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
@@ -0,0 +1,170 @@
+package outis
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type overlapMode string
+
+const (
+	overlapDelay      overlapMode = "delay"
+	overlapSkip       overlapMode = "skip"
+	overlapQueue      overlapMode = "queue"
+	overlapConcurrent overlapMode = "concurrent"
+)
+
+// OverlapPolicy defines what happens when an execution is due
+// while the previous execution is still in progress
+type OverlapPolicy struct {
+	mode  overlapMode
+	limit int
+}
+
+// OverlapDelay delays the next execution until the previous one finishes, it is the default policy
+func OverlapDelay() OverlapPolicy {
+	return OverlapPolicy{mode: overlapDelay, limit: 1}
+}
+
+// OverlapSkip drops the executions due while the previous one is in progress
+func OverlapSkip() OverlapPolicy {
+	return OverlapPolicy{mode: overlapSkip, limit: 1}
+}
+
+// OverlapQueue queues up to limit executions due while the previous one is in progress,
+// running them back-to-back when it finishes. Executions beyond the limit are dropped
+func OverlapQueue(limit int) OverlapPolicy {
+	return OverlapPolicy{mode: overlapQueue, limit: limit}
+}
+
+// OverlapConcurrent allows up to limit executions of the routine in parallel,
+// executions beyond the limit are dropped
+func OverlapConcurrent(limit int) OverlapPolicy {
+	return OverlapPolicy{mode: overlapConcurrent, limit: limit}
+}
+
+// String returns the name of the policy
+func (p OverlapPolicy) String() string {
+	if p.mode == overlapQueue || p.mode == overlapConcurrent {
+		return fmt.Sprintf("%s(%d)", p.mode, p.limit)
+	}
+	return string(p.mode)
+}
+
+func (p OverlapPolicy) validate() error {
+	if (p.mode == overlapQueue || p.mode == overlapConcurrent) && p.limit <= 0 {
+		return errors.New("the overlap policy limit must be greater than zero")
+	}
+
+	return nil
+}
+
+// OverlapMetric defines the state of the overlap
+// policy when the execution was started
+type OverlapMetric struct {
+	Policy  string
+	Running int
+	Queued  int
+	Skipped int
+}
+
+// dispatch starts the execution of the tick according to the overlap policy,
+// only the delay policy waits for the execution to finish
+func (r *routine) dispatch(t tick) {
+	policy := r.ctx.overlap
+	if policy.mode == overlapDelay {
+		r.run(t)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case policy.mode == overlapConcurrent && r.running < policy.limit, r.running == 0:
+		r.running++
+		r.executions.Add(1)
+		r.ctx.Watcher.running()
+		go r.worker(t)
+	case policy.mode == overlapQueue && len(r.queue) < policy.limit:
+		r.queue = append(r.queue, t)
+	default:
+		r.skipped++
+		r.ctx.LogWarn("Execution skipped, previous execution still in progress", LogFields{"overlap_policy": policy.String(), "running": r.running, "skipped": r.skipped})
+	}
+}
+
+// worker runs an execution and the queued executions after it
+func (r *routine) worker(t tick) {
+	defer r.executions.Done()
+	defer r.ctx.Watcher.waiting()
+
+	for {
+		r.run(t)
+
+		r.mu.Lock()
+		if len(r.queue) == 0 || r.ctx.context.Err() != nil {
+			r.running--
+			r.mu.Unlock()
+			return
+		}
+		t, r.queue = r.queue[0], r.queue[1:]
+		r.mu.Unlock()
+	}
+}
+
+// run executes the tick in a new execution context
+func (r *routine) run(t tick) {
+	exec := r.ctx.newExecution()
+	defer exec.contextCancelFunc()
+
+	exec.scheduledAt, exec.catchUp, exec.triggered = t.scheduledAt, t.catchUp, t.triggered
+	exec.previousScheduledAt = r.advance(t)
+	for key, value := range t.metadata {
+		exec.metadata[key] = value
+	}
+
+	// A execução é cancelada quando o watcher perde a liderança
+	if term := exec.Watcher.leadershipTerm(); term != nil {
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-term:
+				exec.contextCancelFunc()
+			case <-finished:
+			}
+		}()
+	}
+
+	exec.overlapMetric = r.overlapMetric()
+	if err := r.execute(exec); err != nil {
+		exec.Watcher.onError(exec, err)
+	}
+}
+
+// advance returns the scheduled time of the previous execution of the schedule, defining the tick being
+// started as the previous one. The triggered executions are out of the schedule and are not kept
+func (r *routine) advance(t tick) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.previous
+	if !t.triggered {
+		r.previous = t.scheduledAt
+	}
+
+	return previous
+}
+
+// overlapMetric returns the state of the overlap policy, restarting the skipped counter
+func (r *routine) overlapMetric() OverlapMetric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metric := OverlapMetric{Policy: r.ctx.overlap.String(), Running: r.running, Queued: len(r.queue), Skipped: r.skipped}
+	r.skipped = 0
+
+	return metric
+}
@@ -0,0 +1,146 @@
+package outis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// overlapRecorder implements IOutis keeping the overlap metrics of the executions
+type overlapRecorder struct {
+	IOutis
+	mu      sync.Mutex
+	metrics []OverlapMetric
+}
+
+func (o *overlapRecorder) Event(ctx Context, event Event) {
+	if metric, ok := event.(EventMetric); ok {
+		o.mu.Lock()
+		o.metrics = append(o.metrics, metric.Overlap)
+		o.mu.Unlock()
+	}
+}
+
+// runOverlap fires ticks of the routine while its first executions are blocked, releasing them before
+// one more tick. It returns the overlap metrics of the executions and the maximum concurrent executions
+func runOverlap(t *testing.T, policy OverlapPolicy, ticks int, blocked int) ([]OverlapMetric, int32) {
+	t.Helper()
+
+	var (
+		clock   = &pausedClock{now: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)}
+		impl    = &overlapRecorder{IOutis: NewOutis()}
+		watch   = Watcher("watcher", "watcher", Logger(nopLogger{}), Impl(impl), Clock(clock))
+		started = make(chan struct{}, 16)
+		release = make(chan struct{})
+
+		running, concurrent int32
+	)
+
+	watch.Go(
+		WithID("routine"),
+		WithName("routine"),
+		WithInterval(time.Minute),
+		WithOverlapPolicy(policy),
+		WithScript(func(Context) error {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				if peak := atomic.LoadInt32(&concurrent); current <= peak || atomic.CompareAndSwapInt32(&concurrent, peak, current) {
+					break
+				}
+			}
+
+			started <- struct{}{}
+			<-release
+			return nil
+		}),
+	)
+
+	// fire expires the timer of the next tick after the routine waits for it
+	fire := func() {
+		require.Eventually(t, func() bool { return len(clock.pending()) == 1 }, 5*time.Second, time.Millisecond)
+		clock.fire()
+	}
+
+	for i := 0; i < ticks; i++ {
+		fire()
+		if i < blocked {
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("the execution of the tick %d did not start", i+1)
+			}
+		}
+	}
+
+	// As execuções bloqueadas são liberadas e um novo horário recebe os horários descartados
+	require.Eventually(t, func() bool { return len(clock.pending()) == 1 }, 5*time.Second, time.Millisecond)
+	close(release)
+	require.Eventually(t, func() bool {
+		snapshot, _ := watch.Routine("routine")
+		return snapshot.Running == 0
+	}, 5*time.Second, time.Millisecond)
+	fire()
+
+	require.Eventually(t, func() bool { return len(clock.pending()) == 1 }, 5*time.Second, time.Millisecond)
+	_, err := watch.Shutdown(context.Background())
+	require.NoError(t, err)
+
+	impl.mu.Lock()
+	defer impl.mu.Unlock()
+
+	return impl.metrics, atomic.LoadInt32(&concurrent)
+}
+
+func TestOverlapSkip(t *testing.T) {
+	metrics, concurrent := runOverlap(t, OverlapSkip(), 4, 1)
+
+	// Os três horários durante a execução são descartados e informados na execução seguinte
+	require.Len(t, metrics, 2)
+	assert.Equal(t, OverlapMetric{Policy: "skip", Running: 1}, metrics[0])
+	assert.Equal(t, OverlapMetric{Policy: "skip", Running: 1, Skipped: 3}, metrics[1])
+	assert.EqualValues(t, 1, concurrent)
+}
+
+func TestOverlapQueue(t *testing.T) {
+	metrics, concurrent := runOverlap(t, OverlapQueue(2), 4, 1)
+
+	// Dois horários são enfileirados e executados em sequência, o terceiro é descartado
+	require.Len(t, metrics, 4)
+	assert.Equal(t, OverlapMetric{Policy: "queue(2)", Running: 1}, metrics[0])
+	assert.Equal(t, OverlapMetric{Policy: "queue(2)", Running: 1, Queued: 1, Skipped: 1}, metrics[1])
+	assert.Equal(t, OverlapMetric{Policy: "queue(2)", Running: 1}, metrics[2])
+	assert.Equal(t, OverlapMetric{Policy: "queue(2)", Running: 1}, metrics[3])
+	assert.EqualValues(t, 1, concurrent)
+}
+
+func TestOverlapConcurrent(t *testing.T) {
+	metrics, concurrent := runOverlap(t, OverlapConcurrent(2), 4, 2)
+
+	// Dois horários são executados em paralelo, os demais são descartados
+	require.Len(t, metrics, 3)
+	assert.EqualValues(t, 2, concurrent)
+
+	skipped := 0
+	for _, metric := range metrics {
+		assert.Equal(t, "concurrent(2)", metric.Policy)
+		skipped += metric.Skipped
+	}
+	assert.Equal(t, 2, skipped)
+	assert.Equal(t, OverlapMetric{Policy: "concurrent(2)", Running: 1, Skipped: 2}, metrics[2])
+}
+
+func TestOverlapPolicyValidate(t *testing.T) {
+	assert.NoError(t, OverlapDelay().validate())
+	assert.NoError(t, OverlapSkip().validate())
+	assert.Error(t, OverlapQueue(0).validate())
+	assert.Error(t, OverlapConcurrent(-1).validate())
+
+	assert.Equal(t, "delay", OverlapDelay().String())
+	assert.Equal(t, "concurrent(3)", OverlapConcurrent(3).String())
+}
@@ -0,0 +1,256 @@
+// Package prometheus exports the events of the outis routines as Prometheus metrics.
+package prometheus
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/Brisanet/outis"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	statusSuccess = "success"
+	statusError   = "error"
+	statusTimeout = "timeout"
+)
+
+// Exporter decorates an implementation of the main interface, translating
+// the events of the routines into Prometheus metrics
+type Exporter struct {
+	outis.IOutis
+
+	registry   *prom.Registry
+	executions *prom.CounterVec
+	latency    *prom.HistogramVec
+	indicators *prom.GaugeVec
+	histograms *histogramCollector
+	retries    *prom.CounterVec
+	restarts   *prom.CounterVec
+	skipped    *prom.CounterVec
+	leader     *prom.GaugeVec
+}
+
+// Option defines the option type of the exporter
+type Option func(*options)
+
+type options struct {
+	namespace      string
+	registry       *prom.Registry
+	latencyBuckets []float64
+}
+
+// WithNamespace defines the namespace of the metrics, by default 'outis' is used
+func WithNamespace(namespace string) Option {
+	return func(opts *options) { opts.namespace = namespace }
+}
+
+// WithRegistry defines the registry where the metrics are registered,
+// by default a new registry is created
+func WithRegistry(registry *prom.Registry) Option {
+	return func(opts *options) { opts.registry = registry }
+}
+
+// WithLatencyBuckets defines the buckets of the execution latency histogram, in seconds
+func WithLatencyBuckets(buckets []float64) Option {
+	return func(opts *options) { opts.latencyBuckets = buckets }
+}
+
+// New creates an exporter that decorates the given implementation of the main interface
+func New(next outis.IOutis, opts ...Option) *Exporter {
+	options := &options{
+		namespace:      "outis",
+		registry:       prom.NewRegistry(),
+		latencyBuckets: prom.DefBuckets,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	labels := []string{"watcher", "routine"}
+	exporter := &Exporter{
+		IOutis:   next,
+		registry: options.registry,
+		executions: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: options.namespace,
+			Name:      "executions_total",
+			Help:      "Total of routine executions by status.",
+		}, append(labels, "status")),
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: options.namespace,
+			Name:      "execution_duration_seconds",
+			Help:      "Duration of the routine executions.",
+			Buckets:   options.latencyBuckets,
+		}, labels),
+		indicators: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace: options.namespace,
+			Name:      "indicator",
+			Help:      "Last value of the indicators recorded by the routines.",
+		}, append(labels, "indicator")),
+		histograms: &histogramCollector{
+			desc: prom.NewDesc(
+				prom.BuildFQName(options.namespace, "", "histogram"),
+				"Values of the histograms recorded by the routines, merged across executions.",
+				append(labels, "histogram"), nil,
+			),
+			histograms: make(map[[3]string]*outis.Histogram),
+		},
+		retries: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: options.namespace,
+			Name:      "retries_total",
+			Help:      "Total of failed execution attempts that were retried.",
+		}, labels),
+		restarts: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: options.namespace,
+			Name:      "restarts_total",
+			Help:      "Total of routine restarts applied by the restart policy.",
+		}, labels),
+		skipped: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: options.namespace,
+			Name:      "skipped_executions_total",
+			Help:      "Total of executions dropped by the overlap policy.",
+		}, labels),
+		leader: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace: options.namespace,
+			Name:      "leader",
+			Help:      "Whether the watcher is the leader (1) or not (0).",
+		}, []string{"watcher"}),
+	}
+
+	exporter.registry.MustRegister(
+		exporter.executions,
+		exporter.latency,
+		exporter.indicators,
+		exporter.histograms,
+		exporter.retries,
+		exporter.restarts,
+		exporter.skipped,
+		exporter.leader,
+	)
+
+	return exporter
+}
+
+// Event translates the event into metrics and forwards it to the decorated implementation
+func (e *Exporter) Event(ctx outis.Context, event outis.Event) {
+	switch ev := event.(type) {
+	case outis.EventMetric:
+		e.observe(ev)
+	case outis.EventRetry:
+		if ev.Retrying {
+			e.retries.WithLabelValues(ev.Watcher.Name, ev.Routine.Name).Inc()
+		}
+	case outis.EventRestart:
+		if ev.Restarting {
+			e.restarts.WithLabelValues(ev.Watcher.Name, ev.Routine.Name).Inc()
+		}
+	case outis.EventLeadership:
+		var value float64
+		if ev.Leader {
+			value = 1
+		}
+		e.leader.WithLabelValues(ev.Watcher.Name).Set(value)
+	}
+
+	e.IOutis.Event(ctx, event)
+}
+
+// OnError delegates the error to the wrapped implementation with outis.HandleError
+func (e *Exporter) OnError(ctx outis.Context, err error) {
+	outis.HandleError(e.IOutis, ctx, err)
+}
+
+// OnRoutineExit notifies the wrapped implementation with outis.HandleRoutineExit
+func (e *Exporter) OnRoutineExit(ctx outis.Context, err error) {
+	outis.HandleRoutineExit(e.IOutis, ctx, err)
+}
+
+func (e *Exporter) observe(metric outis.EventMetric) {
+	var (
+		watcher = metric.Watcher.Name
+		routine = metric.Routine.Name
+		status  = statusSuccess
+	)
+
+	switch {
+	case metric.Timeout:
+		status = statusTimeout
+	case metric.Error != "":
+		status = statusError
+	}
+
+	e.executions.WithLabelValues(watcher, routine, status).Inc()
+	e.latency.WithLabelValues(watcher, routine).Observe(metric.Latency.Seconds())
+	e.skipped.WithLabelValues(watcher, routine).Add(float64(metric.Overlap.Skipped))
+
+	for _, indicator := range metric.Indicators {
+		e.indicators.WithLabelValues(watcher, routine, indicator.GetKey()).Set(indicator.GetValue())
+	}
+
+	for _, histogram := range metric.Histograms {
+		e.histograms.merge(watcher, routine, histogram)
+	}
+}
+
+// histogramCollector merges the histograms recorded by the executions
+// of each routine, exposing them with the buckets defined by the scripts
+type histogramCollector struct {
+	desc       *prom.Desc
+	mu         sync.Mutex
+	histograms map[[3]string]*outis.Histogram
+}
+
+func (c *histogramCollector) merge(watcher, routine string, histogram *outis.Histogram) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := [3]string{watcher, routine, histogram.GetKey()}
+	merged, exists := c.histograms[key]
+	if exists && merged.Merge(histogram) == nil {
+		return
+	}
+
+	// Na primeira execução, ou quando os buckets mudam, o histograma é reiniciado
+	c.histograms[key] = histogram.Clone()
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *histogramCollector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *histogramCollector) Collect(ch chan<- prom.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, histogram := range c.histograms {
+		snapshot := histogram.Snapshot()
+		buckets := make(map[float64]uint64, len(snapshot.Buckets))
+		for _, bucket := range snapshot.Buckets {
+			buckets[bucket.UpperBound] = bucket.Count
+		}
+
+		ch <- prom.MustNewConstHistogram(c.desc, snapshot.Count, snapshot.Sum, buckets, key[0], key[1], key[2])
+	}
+}
+
+// Registry returns the registry of the metrics
+func (e *Exporter) Registry() *prom.Registry {
+	return e.registry
+}
+
+// Handler returns the HTTP handler that serves the metrics
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{Registry: e.registry})
+}
+
+// ListenAndServe serves the metrics on the /metrics path of the address
+func (e *Exporter) ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e.Handler())
+
+	return http.ListenAndServe(addr, mux) //nolint:gosec
+}
@@ -0,0 +1,142 @@
+package prometheus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Brisanet/outis"
+	"github.com/Brisanet/outis/outistest"
+)
+
+// newMetric creates the metric event of an execution of the routine
+func newMetric(latency time.Duration, err string, timeout bool) outis.EventMetric {
+	return outis.EventMetric{
+		Latency: latency,
+		Error:   err,
+		Timeout: timeout,
+		Watcher: outis.WatcherMetric{Name: "watcher"},
+		Routine: outis.RoutineMetric{Name: "routine"},
+	}
+}
+
+func TestExporterCounters(t *testing.T) {
+	var (
+		ctx      = outistest.NewTestContext(t)
+		recorder = outistest.NewRecorder(nil)
+		exporter = New(recorder, WithNamespace("test"))
+	)
+
+	skipped := newMetric(time.Second, "", false)
+	skipped.Overlap.Skipped = 2
+
+	for _, event := range []outis.Event{
+		newMetric(time.Second, "", false),
+		skipped,
+		newMetric(time.Second, "failure", false),
+		newMetric(time.Minute, "timeout", true),
+		outis.EventRetry{Retrying: true, Err: errors.New("failure"), Watcher: outis.WatcherMetric{Name: "watcher"}, Routine: outis.RoutineMetric{Name: "routine"}},
+		outis.EventRetry{Retrying: false, Err: errors.New("failure"), Watcher: outis.WatcherMetric{Name: "watcher"}, Routine: outis.RoutineMetric{Name: "routine"}},
+		outis.EventRestart{Restarting: true, Err: errors.New("failure"), Watcher: outis.WatcherMetric{Name: "watcher"}, Routine: outis.RoutineMetric{Name: "routine"}},
+	} {
+		exporter.Event(ctx, event)
+	}
+
+	expected := `
+# HELP test_executions_total Total of routine executions by status.
+# TYPE test_executions_total counter
+test_executions_total{routine="routine",status="error",watcher="watcher"} 1
+test_executions_total{routine="routine",status="success",watcher="watcher"} 2
+test_executions_total{routine="routine",status="timeout",watcher="watcher"} 1
+# HELP test_retries_total Total of failed execution attempts that were retried.
+# TYPE test_retries_total counter
+test_retries_total{routine="routine",watcher="watcher"} 1
+# HELP test_restarts_total Total of routine restarts applied by the restart policy.
+# TYPE test_restarts_total counter
+test_restarts_total{routine="routine",watcher="watcher"} 1
+# HELP test_skipped_executions_total Total of executions dropped by the overlap policy.
+# TYPE test_skipped_executions_total counter
+test_skipped_executions_total{routine="routine",watcher="watcher"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(exporter.Registry(), strings.NewReader(expected),
+		"test_executions_total", "test_retries_total", "test_restarts_total", "test_skipped_executions_total"))
+
+	// A latência de cada execução é observada no histograma da rotina
+	assert.Equal(t, 1, testutil.CollectAndCount(exporter.latency))
+	assert.Len(t, recorder.Events(), 7, "the events must be forwarded to the decorated implementation")
+}
+
+func TestExporterGauges(t *testing.T) {
+	exporter := New(outistest.NewRecorder(nil))
+
+	// Cada execução registra o indicador no seu próprio contexto
+	for _, value := range []float64{10, 4} {
+		ctx := outistest.NewTestContext(t)
+		metric := newMetric(time.Second, "", false)
+		indicator := ctx.NewIndicator("processed")
+		indicator.Add(value)
+		metric.Indicators = []*outis.Indicator{indicator}
+		exporter.Event(ctx, metric)
+	}
+
+	// O indicador mantém o último valor informado
+	assert.Equal(t, 4.0, testutil.ToFloat64(exporter.indicators.WithLabelValues("watcher", "routine", "processed")))
+
+	ctx := outistest.NewTestContext(t)
+	exporter.Event(ctx, outis.EventLeadership{Leader: true, Watcher: outis.WatcherMetric{Name: "watcher"}})
+	assert.Equal(t, 1.0, testutil.ToFloat64(exporter.leader.WithLabelValues("watcher")))
+
+	exporter.Event(ctx, outis.EventLeadership{Leader: false, Watcher: outis.WatcherMetric{Name: "watcher"}})
+	assert.Equal(t, 0.0, testutil.ToFloat64(exporter.leader.WithLabelValues("watcher")))
+}
+
+func TestExporterHistograms(t *testing.T) {
+	exporter := New(outistest.NewRecorder(nil))
+
+	// observe sends an execution that recorded the values in the histogram with the buckets
+	observe := func(buckets []float64, values ...float64) {
+		ctx := outistest.NewTestContext(t)
+		histogram := ctx.NewHistogram("rows", outis.WithBuckets(buckets...))
+		for _, value := range values {
+			histogram.Add(value)
+		}
+
+		metric := newMetric(time.Second, "", false)
+		metric.Histograms = []*outis.Histogram{histogram}
+		exporter.Event(ctx, metric)
+	}
+
+	// Os histogramas das execuções são somados, com os buckets acumulados do Prometheus
+	observe([]float64{1, 5, 10}, 0.5, 3, 7)
+	observe([]float64{1, 5, 10}, 2, 20)
+
+	expected := `
+# HELP outis_histogram Values of the histograms recorded by the routines, merged across executions.
+# TYPE outis_histogram histogram
+outis_histogram_bucket{histogram="rows",routine="routine",watcher="watcher",le="1"} 1
+outis_histogram_bucket{histogram="rows",routine="routine",watcher="watcher",le="5"} 3
+outis_histogram_bucket{histogram="rows",routine="routine",watcher="watcher",le="10"} 4
+outis_histogram_bucket{histogram="rows",routine="routine",watcher="watcher",le="+Inf"} 5
+outis_histogram_sum{histogram="rows",routine="routine",watcher="watcher"} 32.5
+outis_histogram_count{histogram="rows",routine="routine",watcher="watcher"} 5
+`
+	require.NoError(t, testutil.GatherAndCompare(exporter.Registry(), strings.NewReader(expected), "outis_histogram"))
+
+	// Quando os buckets mudam, o histograma é reiniciado com os novos buckets
+	observe([]float64{2}, 1, 3)
+
+	expected = `
+# HELP outis_histogram Values of the histograms recorded by the routines, merged across executions.
+# TYPE outis_histogram histogram
+outis_histogram_bucket{histogram="rows",routine="routine",watcher="watcher",le="2"} 1
+outis_histogram_bucket{histogram="rows",routine="routine",watcher="watcher",le="+Inf"} 2
+outis_histogram_sum{histogram="rows",routine="routine",watcher="watcher"} 4
+outis_histogram_count{histogram="rows",routine="routine",watcher="watcher"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(exporter.Registry(), strings.NewReader(expected), "outis_histogram"))
+}
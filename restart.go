@@ -0,0 +1,144 @@
+package outis
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RestartMode defines when a finished routine is restarted
+type RestartMode string
+
+const (
+	// RestartNever never restarts the routine, it is the default mode
+	RestartNever RestartMode = "never"
+	// RestartOnFailure restarts the routine when it finishes with an error or a panic
+	RestartOnFailure RestartMode = "on-failure"
+	// RestartAlways restarts the routine whenever it finishes, including the
+	// routines without loop whose execution succeeded
+	RestartAlways RestartMode = "always"
+)
+
+// RestartPolicy defines how the loop of a routine is rebuilt when it finishes. A routine
+// stopped with Watch.Stop or by the shutdown of the watcher is never restarted.
+// The rebuilt loop resumes the schedule, without the catch-up and the first execution before
+// the interval, which only run when the routine starts. A routine without loop executes again
+type RestartPolicy struct {
+	// Mode defines when the routine is restarted
+	Mode RestartMode
+	// Backoff defines the delay before each restart, by default the delay starts at 1 second
+	// and doubles at each restart up to 1 minute, so a routine that fails immediately does
+	// not restart in a busy loop
+	Backoff Backoff
+	// MaxRestarts is the maximum number of restarts within the window, when it is
+	// exceeded the routine fails. By default the number of restarts is not limited
+	MaxRestarts int
+	// Window is the period in which the restarts are counted, by default every restart is counted
+	Window time.Duration
+}
+
+func (p *RestartPolicy) validate() error {
+	switch p.Mode {
+	case RestartNever, RestartOnFailure, RestartAlways:
+	default:
+		return fmt.Errorf("invalid restart mode '%s'", p.Mode)
+	}
+
+	if p.MaxRestarts < 0 || p.Window < 0 {
+		return errors.New("the max restarts and the window of the restart policy must not be negative")
+	}
+
+	return nil
+}
+
+// defaultRestartBackoff is the delay before the restarts when the policy does not define one
+var defaultRestartBackoff = ExponentialBackoff(time.Second, time.Minute, 2)
+
+// restarts returns whether the routine finished with the error must be restarted
+func (p *RestartPolicy) restarts(err error) bool {
+	return p != nil && (p.Mode == RestartAlways || (p.Mode == RestartOnFailure && err != nil))
+}
+
+// supervise runs the loop of the routine, restarting it according to the restart policy.
+// The loop receives whether it is the first one, when the startup steps of the routine run
+func (r *routine) supervise(loop func(startup bool) error) error {
+	var (
+		policy   = r.ctx.restartPolicy
+		backoff  = defaultRestartBackoff
+		restarts []time.Time
+		previous time.Duration
+	)
+
+	if policy != nil && policy.Backoff != nil {
+		backoff = policy.Backoff
+	}
+
+	for startup := true; ; startup = false {
+		err := loop(startup)
+		if !policy.restarts(err) || r.ctx.context.Err() != nil {
+			return err
+		}
+
+		// Somente os reinícios dentro da janela são considerados no limite
+		now := r.ctx.Watcher.now()
+		if policy.Window > 0 {
+			for len(restarts) > 0 && !restarts[0].After(now.Add(-policy.Window)) {
+				restarts = restarts[1:]
+			}
+		}
+
+		var (
+			restarting = policy.MaxRestarts <= 0 || len(restarts) < policy.MaxRestarts
+			delay      time.Duration
+		)
+		if restarting {
+			restarts = append(restarts, now)
+			delay = backoff(r.restart(), previous)
+			previous = delay
+		}
+
+		r.ctx.Watcher.outis.Event(r.ctx, EventRestart{
+			Restart:    r.restartCount(),
+			Delay:      delay,
+			Err:        err,
+			Restarting: restarting,
+			Watcher:    r.ctx.Watcher.metric(),
+			Routine:    r.metric(),
+		})
+
+		if !restarting {
+			if err == nil {
+				return fmt.Errorf("the routine '%s' exceeded the limit of %d restarts", r.ctx.routineID, policy.MaxRestarts)
+			}
+			return err
+		}
+
+		r.setState(RoutineStarting)
+		if !r.ctx.wait(delay) {
+			return r.exitErr(r.ctx.context.Err())
+		}
+	}
+}
+
+// recoverLoop reports a panic of the loop of the routine, which finishes
+// as a failure and is restarted when the restart policy allows it
+func (r *routine) recoverLoop(rec interface{}) error {
+	err := newPanicError(r.ctx.routineID, "", rec)
+	r.ctx.Watcher.onError(r.ctx, err)
+	return err
+}
+
+// restart counts a restart of the routine, returning the number of restarts
+func (r *routine) restart() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.restarts++
+	return r.restarts
+}
+
+func (r *routine) restartCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.restarts
+}
@@ -0,0 +1,279 @@
+package outis
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock is a clock whose time only changes with add, its timers expire immediately
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) NewTimer(time.Duration) ITimer { return realClock{}.NewTimer(0) }
+
+func (c *manualClock) add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// restartRecorder implements IOutis keeping the restart events
+type restartRecorder struct {
+	IOutis
+	mu     sync.Mutex
+	events []EventRestart
+}
+
+func (o *restartRecorder) Event(ctx Context, event Event) {
+	if restart, ok := event.(EventRestart); ok {
+		o.mu.Lock()
+		o.events = append(o.events, restart)
+		o.mu.Unlock()
+	}
+	o.IOutis.Event(ctx, event)
+}
+
+// newSupervisedRoutine creates a routine with the restart policy, in a watcher using the manual clock
+func newSupervisedRoutine(t *testing.T, policy RestartPolicy) (*routine, *manualClock, *restartRecorder) {
+	var (
+		clock = &manualClock{now: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)}
+		impl  = &restartRecorder{IOutis: NewOutis()}
+		watch = newTestWatcher(t, Impl(impl), Clock(clock))
+	)
+
+	ctx, err := watch.NewContext(WithID("routine"), WithName("routine"), WithInterval(time.Minute),
+		WithRestartPolicy(policy), WithScript(func(Context) error { return nil }))
+	require.NoError(t, err)
+
+	return newRoutine(ctx), clock, impl
+}
+
+func TestSuperviseMaxRestarts(t *testing.T) {
+	var (
+		r, _, impl = newSupervisedRoutine(t, RestartPolicy{Mode: RestartOnFailure, MaxRestarts: 2})
+		failure    = errors.New("failure")
+		startups   []bool
+	)
+
+	err := r.supervise(func(startup bool) error {
+		startups = append(startups, startup)
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure, "the error of the last loop must be returned when the limit is exceeded")
+	assert.Equal(t, []bool{true, false, false}, startups, "only the first loop must run the startup steps")
+
+	require.Len(t, impl.events, 3)
+	for i, restarting := range []bool{true, true, false} {
+		assert.Equal(t, restarting, impl.events[i].Restarting)
+		assert.ErrorIs(t, impl.events[i].Err, failure)
+	}
+	assert.Equal(t, 2, impl.events[2].Restart)
+}
+
+func TestSuperviseMaxRestartsWithoutError(t *testing.T) {
+	r, _, _ := newSupervisedRoutine(t, RestartPolicy{Mode: RestartAlways, MaxRestarts: 1})
+
+	var loops int
+	err := r.supervise(func(bool) error {
+		loops++
+		return nil
+	})
+
+	assert.EqualError(t, err, "the routine 'routine' exceeded the limit of 1 restarts")
+	assert.Equal(t, 2, loops)
+}
+
+func TestSuperviseWindow(t *testing.T) {
+	failure := errors.New("failure")
+
+	for _, test := range []struct {
+		name     string
+		interval time.Duration
+		loops    int
+		err      error
+	}{
+		// Os reinícios a cada 40 minutos nunca acumulam 2 na janela de 1 hora
+		{name: "restarts outside the window", interval: 40 * time.Minute, loops: 6, err: nil},
+		{name: "restarts inside the window", interval: 10 * time.Minute, loops: 3, err: failure},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r, clock, _ := newSupervisedRoutine(t, RestartPolicy{Mode: RestartOnFailure, MaxRestarts: 2, Window: time.Hour})
+
+			var loops int
+			err := r.supervise(func(bool) error {
+				if loops++; loops == 6 {
+					return nil
+				}
+
+				clock.add(test.interval)
+				return failure
+			})
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.loops, loops)
+		})
+	}
+}
+
+func TestSuperviseBackoff(t *testing.T) {
+	var (
+		delays     []time.Duration
+		r, _, impl = newSupervisedRoutine(t, RestartPolicy{
+			Mode:        RestartOnFailure,
+			MaxRestarts: 3,
+			Backoff: func(attempt int, previous time.Duration) time.Duration {
+				delays = append(delays, previous)
+				return time.Duration(attempt) * time.Millisecond
+			},
+		})
+	)
+
+	_ = r.supervise(func(bool) error { return errors.New("failure") })
+
+	assert.Equal(t, []time.Duration{0, time.Millisecond, 2 * time.Millisecond}, delays, "the backoff must receive the previous delay")
+	require.Len(t, impl.events, 4)
+	assert.Equal(t, 3*time.Millisecond, impl.events[2].Delay)
+}
+
+func TestRestartWithoutLoop(t *testing.T) {
+	var (
+		impl  = newExitRecorder()
+		watch = newTestWatcher(t, Impl(impl), Clock(&manualClock{now: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)}))
+		runs  = make(chan [2]time.Time, 8)
+	)
+
+	watch.Go(
+		WithID("routine"),
+		WithName("routine"),
+		WithNotUseLoop(),
+		WithRestartPolicy(RestartPolicy{Mode: RestartAlways, MaxRestarts: 2}),
+		WithScript(func(ctx Context) error {
+			runs <- [2]time.Time{ctx.ScheduledAt(), ctx.PreviousScheduledAt()}
+			return nil
+		}),
+	)
+
+	assert.EqualError(t, impl.wait(t).err, "the routine 'routine' exceeded the limit of 2 restarts")
+	close(runs)
+
+	var executions [][2]time.Time
+	for run := range runs {
+		executions = append(executions, run)
+	}
+
+	// Cada reinício executa a rotina novamente, com o horário da execução anterior
+	require.Len(t, executions, 3)
+	assert.True(t, executions[0][1].IsZero())
+	for i := 1; i < len(executions); i++ {
+		assert.True(t, executions[i][1].Equal(executions[i-1][0]))
+	}
+}
+
+func TestRestartPolicyValidate(t *testing.T) {
+	assert.NoError(t, (&RestartPolicy{Mode: RestartAlways, MaxRestarts: 3, Window: time.Minute}).validate())
+	assert.Error(t, (&RestartPolicy{Mode: "sometimes"}).validate())
+	assert.Error(t, (&RestartPolicy{Mode: RestartOnFailure, MaxRestarts: -1}).validate())
+	assert.Error(t, (&RestartPolicy{Mode: RestartOnFailure, Window: -time.Minute}).validate())
+}
+
+// pausedClock is a clock whose timers only expire when fired by the test
+type pausedClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*pausedTimer
+}
+
+type pausedTimer struct {
+	d time.Duration
+	c chan time.Time
+}
+
+func (t *pausedTimer) C() <-chan time.Time { return t.c }
+func (t *pausedTimer) Stop() bool          { return true }
+
+func (c *pausedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *pausedClock) NewTimer(d time.Duration) ITimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &pausedTimer{d: d, c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// pending returns the durations of the timers created and not fired
+func (c *pausedClock) pending() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	durations := make([]time.Duration, 0, len(c.timers))
+	for _, timer := range c.timers {
+		durations = append(durations, timer.d)
+	}
+	return durations
+}
+
+// fire expires the timers created and not fired
+func (c *pausedClock) fire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, timer := range c.timers {
+		c.now = c.now.Add(timer.d)
+		timer.c <- c.now
+	}
+	c.timers = nil
+}
+
+func TestRestartDefaultBackoff(t *testing.T) {
+	var (
+		clock = &pausedClock{now: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)}
+		impl  = &restartRecorder{IOutis: NewOutis()}
+		watch = newTestWatcher(t, Impl(impl), Clock(clock))
+		runs  int32
+	)
+
+	// Uma rotina que falha imediatamente, com reinícios ilimitados e sem backoff
+	watch.Go(
+		WithID("routine"),
+		WithName("routine"),
+		WithNotUseLoop(),
+		WithRestartPolicy(RestartPolicy{Mode: RestartAlways}),
+		WithScript(func(Context) error {
+			atomic.AddInt32(&runs, 1)
+			return errors.New("failure")
+		}),
+	)
+
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		require.Eventually(t, func() bool { return len(clock.pending()) == 1 }, 5*time.Second, time.Millisecond)
+		assert.Equal(t, []time.Duration{delay}, clock.pending(), "restart %d", i+1)
+
+		// Enquanto a espera não termina, a rotina não é executada novamente
+		time.Sleep(20 * time.Millisecond)
+		assert.EqualValues(t, i+1, atomic.LoadInt32(&runs))
+
+		clock.fire()
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == int32(i+2) }, 5*time.Second, time.Millisecond)
+	}
+}
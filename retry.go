@@ -1,24 +1,153 @@
 package outis
 
-// retry allowes a given method to be retried x amount of times.
-type retry struct{ amount, retries int8 }
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
 
-// Go returns the settings for using the Attempt method
-func (ctx *ContextImpl) Retry(retries int8) *retry {
-	return &retry{retries: retries}
+// Retrier allows a given method to be retried x amount of times.
+type Retrier struct {
+	ctx             *ContextImpl
+	amount, retries int8
+	backoff         Backoff
+}
+
+// Retry returns the settings for using the Attempt method
+func (ctx *ContextImpl) Retry(retries int8) *Retrier {
+	return &Retrier{ctx: ctx, amount: retries}
+}
+
+// WithBackoff defines the delay between the attempts
+func (r *Retrier) WithBackoff(backoff Backoff) *Retrier {
+	r.backoff = backoff
+	return r
 }
 
 // The attempt attempts the given method for a given amount of retries
-// If the method still fails after the set limit is a error returned.
-func (r *retry) Attempt(method func() error) (err error) {
-	if err = method(); err == nil {
-		return nil
+// If the method still fails after the set limit, or the context is done, is a error returned.
+func (r *Retrier) Attempt(method func() error) (err error) {
+	var delay time.Duration
+
+	for {
+		if err = method(); err == nil {
+			return nil
+		}
+
+		if r.retries >= r.amount {
+			return err
+		}
+
+		r.retries++
+		if r.backoff != nil {
+			delay = r.backoff(int(r.retries), delay)
+		}
+
+		if !r.ctx.wait(delay) {
+			return err
+		}
+	}
+}
+
+// Backoff defines the delay before the next attempt, based on the
+// number of the failed attempt and on the previous delay
+type Backoff func(attempt int, previous time.Duration) time.Duration
+
+// decorrelatedJitterMinBase is the base delay of DecorrelatedJitterBackoff when the given one
+// is not positive, since the delays would never grow from zero
+const decorrelatedJitterMinBase = time.Millisecond
+
+// ConstantBackoff waits the same delay between attempts
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration { return delay }
+}
+
+// ExponentialBackoff multiplies the delay at each attempt, limited to the maximum delay.
+// The multiplier must be greater than 1, otherwise the delay would never grow, so a
+// multiplier less than or equal to 1, or NaN, is replaced by 2
+func ExponentialBackoff(initial, max time.Duration, multiplier float64) Backoff {
+	if !(multiplier > 1) {
+		multiplier = 2
+	}
+
+	return func(attempt int, _ time.Duration) time.Duration {
+		delay := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+		if max > 0 && delay > float64(max) {
+			return max
+		}
+		if delay >= math.MaxInt64 {
+			return time.Duration(math.MaxInt64)
+		}
+		return time.Duration(delay)
+	}
+}
+
+// DecorrelatedJitterBackoff waits a random delay between the base delay and
+// three times the previous delay, limited to the maximum delay.
+// A base delay not greater than zero is replaced by 1ms, so that the delays grow
+func DecorrelatedJitterBackoff(base, max time.Duration) Backoff {
+	if base <= 0 {
+		base = decorrelatedJitterMinBase
+	}
+
+	return func(_ int, previous time.Duration) time.Duration {
+		if previous < base {
+			previous = base
+		}
+
+		// O triplo do atraso anterior é limitado para não estourar o int64
+		upper := time.Duration(math.MaxInt64)
+		if previous <= upper/3 {
+			upper = previous * 3
+		}
+
+		delay := base + time.Duration(rand.Int63n(int64(upper-base)))
+		if max > 0 && delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+// RetryPolicy defines how the script execution is retried when it fails
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one
+	MaxAttempts int
+	// MaxElapsedTime is the maximum time spent since the first attempt
+	MaxElapsedTime time.Duration
+	// Backoff defines the delay between attempts, by default there is no delay
+	Backoff Backoff
+	// Retryable defines whether an error must be retried, by default every error is retried
+	Retryable func(error) bool
+}
+
+func (p *RetryPolicy) validate() error {
+	if p.MaxAttempts <= 0 && p.MaxElapsedTime <= 0 {
+		return errors.New("the retry policy requires the max attempts or the max elapsed time")
+	}
+
+	return nil
+}
+
+// next returns the delay before the next attempt and if the execution must be retried
+func (p *RetryPolicy) next(attempt int, elapsed time.Duration, previous time.Duration, err error) (time.Duration, bool) {
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if p.Retryable != nil && !p.Retryable(err) {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if p.Backoff != nil {
+		delay = p.Backoff(attempt, previous)
 	}
 
-	if r.retries >= r.amount {
-		return err
+	if p.MaxElapsedTime > 0 && elapsed+delay > p.MaxElapsedTime {
+		return 0, false
 	}
 
-	r.retries++
-	return r.Attempt(method)
+	return delay, true
 }
@@ -0,0 +1,246 @@
+package outis
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	backoff := ConstantBackoff(time.Second)
+	for attempt := 1; attempt <= 3; attempt++ {
+		assert.Equal(t, time.Second, backoff(attempt, time.Duration(attempt)*time.Minute))
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		initial    time.Duration
+		max        time.Duration
+		multiplier float64
+		delays     []time.Duration
+	}{
+		{
+			name: "multiplier", initial: time.Second, max: time.Minute, multiplier: 3,
+			delays: []time.Duration{time.Second, 3 * time.Second, 9 * time.Second, 27 * time.Second, time.Minute, time.Minute},
+		},
+		{
+			name: "fractional multiplier", initial: 100 * time.Millisecond, multiplier: 1.5,
+			delays: []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 225 * time.Millisecond},
+		},
+		{
+			name: "multiplier equal to one replaced by two", initial: time.Second, multiplier: 1,
+			delays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name: "multiplier less than one replaced by two", initial: time.Second, multiplier: 0.5,
+			delays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name: "NaN multiplier replaced by two", initial: time.Second, multiplier: math.NaN(),
+			delays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			backoff := ExponentialBackoff(test.initial, test.max, test.multiplier)
+			for i, delay := range test.delays {
+				assert.Equal(t, delay, backoff(i+1, 0), "attempt %d", i+1)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoffOverflow(t *testing.T) {
+	// Sem máximo, o atraso é limitado ao maior time.Duration
+	assert.Equal(t, time.Duration(math.MaxInt64), ExponentialBackoff(time.Second, 0, 2)(200, 0))
+	assert.Equal(t, time.Hour, ExponentialBackoff(time.Second, time.Hour, 2)(200, 0))
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	var (
+		base    = 10 * time.Millisecond
+		max     = time.Second
+		backoff = DecorrelatedJitterBackoff(base, max)
+		delay   time.Duration
+	)
+
+	for attempt := 1; attempt <= 1000; attempt++ {
+		previous := delay
+		if previous < base {
+			previous = base
+		}
+
+		delay = backoff(attempt, delay)
+		require.GreaterOrEqual(t, delay, base)
+		require.LessOrEqual(t, delay, max)
+		if 3*previous < max {
+			require.Less(t, delay, 3*previous, "the delay must be less than three times the previous one")
+		}
+	}
+
+	// Atrasos anteriores muito grandes não estouram o limite do int64
+	huge := DecorrelatedJitterBackoff(base, 0)(1, time.Duration(math.MaxInt64))
+	assert.GreaterOrEqual(t, huge, base)
+}
+
+func TestDecorrelatedJitterBackoffZeroBase(t *testing.T) {
+	var (
+		backoff = DecorrelatedJitterBackoff(0, 0)
+		delay   time.Duration
+		grew    bool
+	)
+
+	// Com base zero, os atrasos partem de 1ms e crescem
+	for attempt := 1; attempt <= 20; attempt++ {
+		delay = backoff(attempt, delay)
+		require.GreaterOrEqual(t, delay, decorrelatedJitterMinBase)
+		grew = grew || delay > 2*decorrelatedJitterMinBase
+	}
+	assert.True(t, grew, "the delays must grow from the base")
+}
+
+func TestRetrierAttempt(t *testing.T) {
+	ctx, err := newTestWatcher(t).NewContext(WithID("routine"), WithName("routine"), WithInterval(time.Minute), WithScript(func(Context) error { return nil }))
+	require.NoError(t, err)
+
+	failure := errors.New("failure")
+
+	t.Run("success after failures", func(t *testing.T) {
+		var (
+			calls     int
+			previous  []time.Duration
+			recording = func(attempt int, p time.Duration) time.Duration {
+				previous = append(previous, p)
+				return time.Duration(attempt) * time.Millisecond
+			}
+		)
+
+		err := ctx.Retry(3).WithBackoff(recording).Attempt(func() error {
+			if calls++; calls < 3 {
+				return failure
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{0, time.Millisecond}, previous, "the backoff must receive the previous delay")
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		var calls int
+		err := ctx.Retry(2).Attempt(func() error {
+			calls++
+			return failure
+		})
+
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 3, calls, "the method must be attempted once plus the retries")
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		exec := ctx.newExecution()
+		exec.contextCancelFunc()
+
+		var calls int
+		err := exec.Retry(5).WithBackoff(ConstantBackoff(time.Hour)).Attempt(func() error {
+			calls++
+			return failure
+		})
+
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 1, calls, "the attempts must stop when the context is done")
+	})
+}
+
+func TestRetryPolicyNext(t *testing.T) {
+	var (
+		failure   = errors.New("failure")
+		permanent = errors.New("permanent")
+		policy    = RetryPolicy{
+			MaxAttempts:    3,
+			MaxElapsedTime: time.Minute,
+			Backoff:        ConstantBackoff(10 * time.Second),
+			Retryable:      func(err error) bool { return !errors.Is(err, permanent) },
+		}
+	)
+
+	for _, test := range []struct {
+		name     string
+		attempt  int
+		elapsed  time.Duration
+		err      error
+		delay    time.Duration
+		retrying bool
+	}{
+		{name: "retried", attempt: 1, err: failure, delay: 10 * time.Second, retrying: true},
+		{name: "max attempts", attempt: 3, err: failure},
+		{name: "not retryable", attempt: 1, err: permanent},
+		{name: "max elapsed time", attempt: 2, elapsed: 55 * time.Second, err: failure},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			delay, retrying := policy.next(test.attempt, test.elapsed, 0, test.err)
+			assert.Equal(t, test.delay, delay)
+			assert.Equal(t, test.retrying, retrying)
+		})
+	}
+
+	assert.Error(t, (&RetryPolicy{}).validate())
+	assert.NoError(t, (&RetryPolicy{MaxElapsedTime: time.Minute}).validate())
+}
+
+// retryRecorder implements IOutis keeping the retry events
+type retryRecorder struct {
+	IOutis
+	mu     sync.Mutex
+	events []EventRetry
+}
+
+func (o *retryRecorder) Event(ctx Context, event Event) {
+	if retry, ok := event.(EventRetry); ok {
+		o.mu.Lock()
+		o.events = append(o.events, retry)
+		o.mu.Unlock()
+	}
+	o.IOutis.Event(ctx, event)
+}
+
+func TestRetryPolicyExecution(t *testing.T) {
+	var (
+		impl     = &retryRecorder{IOutis: NewOutis()}
+		watch    = newTestWatcher(t, Impl(impl))
+		failure  = errors.New("failure")
+		attempts []int
+	)
+
+	ctx, err := watch.NewContext(
+		WithID("routine"),
+		WithName("routine"),
+		WithInterval(time.Minute),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: ConstantBackoff(time.Millisecond)}),
+		WithScript(func(ctx Context) error {
+			attempts = append(attempts, ctx.Attempt())
+			return failure
+		}),
+	)
+	require.NoError(t, err)
+
+	exec := ctx.newExecution()
+	defer exec.contextCancelFunc()
+
+	assert.ErrorIs(t, exec.execute(), failure)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+
+	require.Len(t, impl.events, 3)
+	for i, retrying := range []bool{true, true, false} {
+		assert.Equal(t, i+1, impl.events[i].Attempt)
+		assert.Equal(t, retrying, impl.events[i].Retrying)
+	}
+	assert.Equal(t, time.Millisecond, impl.events[0].Delay)
+}
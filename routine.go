@@ -0,0 +1,439 @@
+package outis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// routine defines a routine started by the watcher
+type routine struct {
+	ctx     *ContextImpl
+	done    chan struct{}
+	trigger chan tick
+
+	mu         sync.Mutex
+	state      RoutineState
+	stopping   bool
+	exitError  error
+	running    int
+	queue      []tick
+	skipped    int
+	paused     bool
+	nextRun    time.Time
+	previous   time.Time
+	inflight   map[*ContextImpl]struct{}
+	executions sync.WaitGroup
+
+	runs                uint64
+	restarts            int
+	consecutiveFailures uint64
+	lastID              string
+	lastErr             string
+	lastStartedAt       time.Time
+	lastFinishedAt      time.Time
+}
+
+// RoutineState defines the state of a routine
+type RoutineState string
+
+const (
+	// RoutineStarting is the state of a routine being initialized, before its first schedule
+	RoutineStarting RoutineState = "starting"
+	// RoutineWaiting is the state of a routine waiting for the next execution
+	RoutineWaiting RoutineState = "waiting"
+	// RoutineRunning is the state of a routine with executions in progress
+	RoutineRunning RoutineState = "running"
+	// RoutineStopped is the state of a routine whose loop has finished without error,
+	// because it was stopped, the watcher was shut down or its single execution succeeded
+	RoutineStopped RoutineState = "stopped"
+	// RoutineFailed is the state of a routine whose loop has finished with an error
+	RoutineFailed RoutineState = "failed"
+)
+
+// RoutineSnapshot defines the state of a routine at the moment it was taken
+type RoutineSnapshot struct {
+	ID                  string         `json:"id"`
+	Name                string         `json:"name"`
+	Desc                string         `json:"desc,omitempty"`
+	Path                string         `json:"path"`
+	Interval            time.Duration  `json:"interval,omitempty"`
+	Cron                string         `json:"cron,omitempty"`
+	Window              *RoutineWindow `json:"window,omitempty"`
+	State               RoutineState   `json:"state"`
+	Paused              bool           `json:"paused"`
+	Running             int            `json:"running"`
+	NextRun             time.Time      `json:"next_run"`
+	RunCount            uint64         `json:"run_count"`
+	ConsecutiveFailures uint64         `json:"consecutive_failures"`
+	LastExecutionID     string         `json:"last_execution_id,omitempty"`
+	LastError           string         `json:"last_error,omitempty"`
+	LastStartedAt       time.Time      `json:"last_started_at"`
+	LastFinishedAt      time.Time      `json:"last_finished_at"`
+	Restarts            int            `json:"restarts"`
+	ExitError           string         `json:"exit_error,omitempty"`
+}
+
+// RoutineWindow defines the days, hours and minutes in which a routine is executed,
+// the bounds not defined in the routine are nil or empty
+type RoutineWindow struct {
+	StartHour   *uint          `json:"start_hour,omitempty"`
+	EndHour     *uint          `json:"end_hour,omitempty"`
+	StartMinute *uint          `json:"start_minute,omitempty"`
+	EndMinute   *uint          `json:"end_minute,omitempty"`
+	Weekdays    []time.Weekday `json:"weekdays,omitempty"`
+	MonthDays   []int          `json:"month_days,omitempty"`
+	Months      []time.Month   `json:"months,omitempty"`
+	Holidays    bool           `json:"holidays,omitempty"`
+}
+
+// tick defines an execution of the routine to be started
+type tick struct {
+	// scheduledAt is the logical time of the execution, when zero the execution time is used
+	scheduledAt time.Time
+	// catchUp defines whether the execution replaces one missed while the watcher was down
+	catchUp bool
+	// triggered defines whether the execution was requested out of the schedule
+	triggered bool
+	// metadata is added to the execution context
+	metadata Metadata
+}
+
+func newRoutine(ctx *ContextImpl) *routine {
+	return &routine{
+		ctx:      ctx,
+		state:    RoutineStarting,
+		done:     make(chan struct{}),
+		trigger:  make(chan tick, 1),
+		inflight: make(map[*ContextImpl]struct{}),
+	}
+}
+
+// metric returns the routine data used in metrics and reports
+func (r *routine) metric() RoutineMetric {
+	return r.ctx.routineMetric()
+}
+
+// execute executes the script in the execution context, keeping it as in progress
+// so that it can be cancelled, and records the result of the execution
+func (r *routine) execute(exec *ContextImpl) error {
+	r.mu.Lock()
+	r.inflight[exec] = struct{}{}
+	r.mu.Unlock()
+
+	startedAt := exec.Watcher.now()
+	err := exec.execute()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.inflight, exec)
+	r.runs++
+	r.lastID, r.lastErr = exec.id.ToString(), ""
+	r.lastStartedAt, r.lastFinishedAt = startedAt, exec.Watcher.now()
+	if err != nil {
+		r.lastErr = err.Error()
+		r.consecutiveFailures++
+	} else {
+		r.consecutiveFailures = 0
+	}
+
+	return err
+}
+
+// snapshot returns the current state of the routine
+func (r *routine) snapshot() RoutineSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := RoutineSnapshot{
+		ID:                  r.ctx.routineID.ToString(),
+		Name:                r.ctx.name,
+		Desc:                r.ctx.Desc,
+		Path:                r.ctx.Path,
+		Cron:                r.ctx.Cron,
+		State:               r.state,
+		Paused:              r.paused,
+		Running:             len(r.inflight),
+		NextRun:             r.nextRun,
+		RunCount:            r.runs,
+		Restarts:            r.restarts,
+		ConsecutiveFailures: r.consecutiveFailures,
+		LastExecutionID:     r.lastID,
+		LastError:           r.lastErr,
+		LastStartedAt:       r.lastStartedAt,
+		LastFinishedAt:      r.lastFinishedAt,
+	}
+
+	if r.ctx.schedule == nil {
+		snapshot.Interval = r.ctx.Interval
+	}
+
+	if period := r.ctx.period; period.hourSet || period.minuteSet || len(period.weekdays) > 0 ||
+		len(period.monthDays) > 0 || len(period.months) > 0 || period.holidays != nil {
+		snapshot.Window = &RoutineWindow{
+			Weekdays:  period.weekdays,
+			MonthDays: period.monthDays,
+			Months:    period.months,
+			Holidays:  period.holidays != nil,
+		}
+		if period.hourSet {
+			snapshot.Window.StartHour, snapshot.Window.EndHour = &period.startHour, &period.endHour
+		}
+		if period.minuteSet {
+			snapshot.Window.StartMinute, snapshot.Window.EndMinute = &period.startMinute, &period.endMinute
+		}
+	}
+
+	if r.exitError != nil {
+		snapshot.ExitError = r.exitError.Error()
+	}
+
+	if r.state != RoutineStopped && r.state != RoutineFailed && len(r.inflight) > 0 {
+		snapshot.State = RoutineRunning
+	}
+
+	return snapshot
+}
+
+func (r *routine) setState(state RoutineState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state = state
+}
+
+// stop cancels the context of the routine, finishing its loop and its executions in progress
+func (r *routine) stop() {
+	r.mu.Lock()
+	r.stopping = true
+	r.mu.Unlock()
+
+	r.ctx.contextCancelFunc()
+}
+
+// exitErr returns the error of a finished routine, the cancellation caused by
+// the shutdown of the watcher or by the stop of the routine is not considered an error
+func (r *routine) exitErr(err error) error {
+	r.mu.Lock()
+	stopping := r.stopping
+	r.mu.Unlock()
+
+	if (stopping || r.ctx.Watcher.context.Err() != nil) && errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	return err
+}
+
+// exit defines the final state of the routine and notifies its termination
+func (r *routine) exit(err error) {
+	r.mu.Lock()
+	r.state, r.exitError = RoutineStopped, err
+	if err != nil {
+		r.state = RoutineFailed
+	}
+	r.mu.Unlock()
+
+	r.ctx.contextCancelFunc()
+	HandleRoutineExit(r.ctx.Watcher.outis, r.ctx, err)
+	close(r.done)
+}
+
+// cancel cancels the executions in progress, returning how many were cancelled
+func (r *routine) cancel() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for exec := range r.inflight {
+		exec.contextCancelFunc()
+	}
+
+	return len(r.inflight)
+}
+
+// requestTrigger requests an execution out of the schedule, it is started as soon as
+// the routine is waiting for the next execution. It returns false when another request is pending
+func (r *routine) requestTrigger(metadata Metadata) bool {
+	copyMetadata := make(Metadata, len(metadata))
+	for key, value := range metadata {
+		copyMetadata[key] = value
+	}
+
+	select {
+	case r.trigger <- tick{metadata: copyMetadata, triggered: true}:
+		return true
+	default:
+		return false
+	}
+}
+
+// setPaused pauses or resumes the scheduled executions of the routine
+func (r *routine) setPaused(paused bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paused = paused
+}
+
+func (r *routine) isPaused() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.paused
+}
+
+func (r *routine) setNextRun(next time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextRun = next
+}
+
+// Routines returns the snapshots of the routines started by the watcher, in the order they were registered
+func (watch *Watch) Routines() []RoutineSnapshot {
+	routines := watch.registry.list()
+	snapshots := make([]RoutineSnapshot, 0, len(routines))
+	for _, r := range routines {
+		snapshots = append(snapshots, r.snapshot())
+	}
+
+	return snapshots
+}
+
+// Routine returns the snapshot of the routine with the identifier, if it was started by the watcher
+func (watch *Watch) Routine(id ID) (RoutineSnapshot, bool) {
+	r, found := watch.registry.find(id)
+	if !found {
+		return RoutineSnapshot{}, false
+	}
+
+	return r.snapshot(), true
+}
+
+// Pause pauses the scheduled executions of the routine, the executions in progress
+// are not affected and the routine can still be executed with TriggerNow
+func (watch *Watch) Pause(routineID ID) error {
+	r, found := watch.registry.find(routineID)
+	if !found {
+		return ErrRoutineNotFound
+	}
+
+	r.setPaused(true)
+	return nil
+}
+
+// Resume resumes the scheduled executions of a paused routine
+func (watch *Watch) Resume(routineID ID) error {
+	r, found := watch.registry.find(routineID)
+	if !found {
+		return ErrRoutineNotFound
+	}
+
+	r.setPaused(false)
+	return nil
+}
+
+// Stop stops the routine while the other routines of the watcher keep running. No new execution
+// is started and the executions in progress are cancelled, the routine is stopped when they finish
+// and its termination is notified to OnRoutineExit
+func (watch *Watch) Stop(routineID ID) error {
+	r, found := watch.registry.find(routineID)
+	if !found {
+		return ErrRoutineNotFound
+	}
+
+	r.stop()
+	return nil
+}
+
+// TriggerNow requests an execution of the routine out of the schedule, even if it is paused.
+// The metadata is added to the execution context, and the execution starts as soon as the routine
+// is waiting for the next execution, following the overlap policy
+func (watch *Watch) TriggerNow(routineID ID, metadata Metadata) error {
+	r, found := watch.registry.find(routineID)
+	if !found {
+		return ErrRoutineNotFound
+	}
+
+	if !r.requestTrigger(metadata) {
+		return ErrTriggerPending
+	}
+
+	return nil
+}
+
+// registry keeps the routines started by the watcher
+type registry struct {
+	mu       sync.Mutex
+	routines []*routine
+	closed   bool
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newRegistry() *registry {
+	return &registry{stopped: make(chan struct{})}
+}
+
+// add registers a routine. It fails when the watcher is shutting down or when a routine
+// with the same identifier is running, a finished routine is replaced by the new one
+func (reg *registry) add(r *routine) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.closed {
+		return errWatcherClosed
+	}
+
+	for i, registered := range reg.routines {
+		if registered.ctx.routineID != r.ctx.routineID {
+			continue
+		}
+
+		select {
+		case <-registered.done:
+			reg.routines[i] = r
+			return nil
+		default:
+			return fmt.Errorf("%w: '%s'", ErrDuplicateRoutine, r.ctx.routineID)
+		}
+	}
+
+	reg.routines = append(reg.routines, r)
+	return nil
+}
+
+// list returns the registered routines
+func (reg *registry) list() []*routine {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	return append([]*routine(nil), reg.routines...)
+}
+
+// find returns the registered routine with the identifier
+func (reg *registry) find(id ID) (*routine, bool) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for _, r := range reg.routines {
+		if r.ctx.routineID == id {
+			return r, true
+		}
+	}
+
+	return nil, false
+}
+
+// close prevents new routines from being registered and returns the registered ones
+func (reg *registry) close() []*routine {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.closed = true
+	return append([]*routine(nil), reg.routines...)
+}
+
+// stop signals that the shutdown has finished
+func (reg *registry) stop() {
+	reg.stopOnce.Do(func() { close(reg.stopped) })
+}
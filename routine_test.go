@@ -0,0 +1,143 @@
+package outis
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoRejectsDuplicateRoutine(t *testing.T) {
+	var (
+		impl  = newExitRecorder()
+		watch = newTestWatcher(t, Impl(impl))
+		opts  = []Option{WithID("routine"), WithName("routine"), WithInterval(time.Hour), WithScript(func(Context) error { return nil })}
+	)
+
+	watch.Go(opts...)
+	require.Eventually(t, func() bool {
+		snapshot, ok := watch.Routine("routine")
+		return ok && snapshot.State == RoutineWaiting
+	}, 5*time.Second, time.Millisecond)
+
+	watch.Go(opts...)
+	exit := impl.wait(t)
+	assert.True(t, errors.Is(exit.err, ErrDuplicateRoutine), "unexpected error: %v", exit.err)
+	assert.Len(t, watch.Routines(), 1)
+
+	snapshot, _ := watch.Routine("routine")
+	assert.Equal(t, RoutineWaiting, snapshot.State, "the running routine must not be affected by the duplicate")
+
+	// A rotina finalizada pode ser criada novamente
+	require.NoError(t, watch.Stop("routine"))
+	assert.NoError(t, impl.wait(t).err)
+
+	watch.Go(opts...)
+	require.Eventually(t, func() bool {
+		snapshot, ok := watch.Routine("routine")
+		return ok && snapshot.State == RoutineWaiting
+	}, 5*time.Second, time.Millisecond)
+	assert.Len(t, watch.Routines(), 1)
+}
+
+func TestGoDoesNotRegisterInvalidRoutine(t *testing.T) {
+	var (
+		impl  = newExitRecorder()
+		watch = newTestWatcher(t, Impl(impl))
+	)
+
+	watch.Go(WithID("routine"), WithScript(func(Context) error { return nil }))
+
+	exit := impl.wait(t)
+	assert.EqualError(t, exit.err, "the routine name is required")
+	assert.Empty(t, watch.Routines())
+}
+
+func TestRoutineSnapshot(t *testing.T) {
+	var (
+		start = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+		clock = &pausedClock{now: start}
+		watch = newTestWatcher(t, Clock(clock))
+		runs  int32
+	)
+
+	watch.Go(
+		WithID("routine"),
+		WithName("routine"),
+		WithDesc("description"),
+		WithInterval(time.Minute),
+		WithHours(8, 18),
+		WithLocation(time.UTC),
+		WithScript(func(Context) error {
+			// As duas primeiras execuções falham
+			if atomic.AddInt32(&runs, 1) <= 2 {
+				return errors.New("failure")
+			}
+			return nil
+		}),
+	)
+
+	// snapshotAfter fires the next tick and returns the snapshot once the routine waits for the following one
+	snapshotAfter := func(runCount uint64) RoutineSnapshot {
+		t.Helper()
+
+		require.Eventually(t, func() bool { return len(clock.pending()) == 1 }, 5*time.Second, time.Millisecond)
+		clock.fire()
+
+		// A rotina espera o próximo horário após registrar o resultado da execução
+		require.Eventually(t, func() bool {
+			snapshot, _ := watch.Routine("routine")
+			return snapshot.RunCount == runCount && len(clock.pending()) == 1
+		}, 5*time.Second, time.Millisecond)
+
+		snapshot, _ := watch.Routine("routine")
+		assert.Equal(t, RoutineWaiting, snapshot.State)
+		return snapshot
+	}
+
+	require.Eventually(t, func() bool { return len(clock.pending()) == 1 }, 5*time.Second, time.Millisecond)
+	snapshot, found := watch.Routine("routine")
+	require.True(t, found)
+
+	assert.Equal(t, "routine", snapshot.ID)
+	assert.Equal(t, "routine", snapshot.Name)
+	assert.Equal(t, "description", snapshot.Desc)
+	assert.NotEmpty(t, snapshot.Path)
+	assert.Equal(t, time.Minute, snapshot.Interval)
+	require.NotNil(t, snapshot.Window)
+	assert.Equal(t, uint(8), *snapshot.Window.StartHour)
+	assert.Equal(t, uint(18), *snapshot.Window.EndHour)
+	assert.Nil(t, snapshot.Window.StartMinute)
+	assert.Equal(t, RoutineWaiting, snapshot.State)
+	assert.Equal(t, start.Add(time.Minute), snapshot.NextRun)
+	assert.Zero(t, snapshot.RunCount)
+	assert.Empty(t, snapshot.LastExecutionID)
+
+	// As falhas consecutivas são acumuladas até uma execução com sucesso
+	snapshot = snapshotAfter(1)
+	assert.Equal(t, "failure", snapshot.LastError)
+	assert.EqualValues(t, 1, snapshot.ConsecutiveFailures)
+	assert.NotEmpty(t, snapshot.LastExecutionID)
+	assert.Equal(t, start.Add(time.Minute), snapshot.LastStartedAt)
+	assert.Equal(t, start.Add(time.Minute), snapshot.LastFinishedAt)
+	assert.Equal(t, start.Add(2*time.Minute), snapshot.NextRun)
+	failedID := snapshot.LastExecutionID
+
+	snapshot = snapshotAfter(2)
+	assert.EqualValues(t, 2, snapshot.ConsecutiveFailures)
+	assert.NotEqual(t, failedID, snapshot.LastExecutionID)
+
+	snapshot = snapshotAfter(3)
+	assert.Empty(t, snapshot.LastError)
+	assert.Zero(t, snapshot.ConsecutiveFailures)
+	assert.Equal(t, start.Add(3*time.Minute), snapshot.LastStartedAt)
+	assert.Equal(t, start.Add(4*time.Minute), snapshot.NextRun)
+
+	assert.Equal(t, []RoutineSnapshot{snapshot}, watch.Routines())
+
+	_, found = watch.Routine("unknown")
+	assert.False(t, found)
+}
@@ -0,0 +1,87 @@
+package outis
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ShutdownReport defines the result of the shutdown of a watcher
+type ShutdownReport struct {
+	// Drained are the routines that finished before the deadline
+	Drained []RoutineMetric
+	// Abandoned are the routines still running when the deadline was reached
+	Abandoned []RoutineMetric
+}
+
+// shutdownSignals defines the signals that shut down the watcher
+type shutdownSignals struct {
+	timeout time.Duration
+	signals []os.Signal
+}
+
+// Shutdown cancels the context of every routine, so no new execution is started,
+// and waits for the executions in progress to finish until the context is done.
+// The executions still being recorded in the history are also awaited.
+// The error of the context is returned when any routine was abandoned
+func (watch *Watch) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	var (
+		routines = watch.registry.close()
+		report   ShutdownReport
+	)
+
+	defer watch.registry.stop()
+	watch.cancel()
+
+	for _, r := range routines {
+		select {
+		case <-r.done:
+			report.Drained = append(report.Drained, r.metric())
+		case <-ctx.Done():
+			select {
+			case <-r.done:
+				report.Drained = append(report.Drained, r.metric())
+			default:
+				report.Abandoned = append(report.Abandoned, r.metric())
+			}
+		}
+	}
+
+	if len(report.Abandoned) > 0 {
+		return report, ctx.Err()
+	}
+
+	return report, watch.history.flush(ctx)
+}
+
+// handleSignals waits for one of the signals to shut down the watcher
+func (watch *Watch) handleSignals(timeout time.Duration, signals []os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+
+	wait := make(chan os.Signal, 1)
+	signal.Notify(wait, signals...)
+	defer signal.Stop(wait)
+
+	select {
+	case sig := <-wait:
+		watch.log.Info("Shutting down watcher, signal received: " + sig.String())
+	case <-watch.context.Done():
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	report, err := watch.Shutdown(ctx)
+	fields := LogFields{"drained": report.Drained, "abandoned": report.Abandoned}
+	if err != nil {
+		watch.log.Error(err, fields)
+		return
+	}
+
+	watch.log.Info("Watcher shut down", fields)
+}
@@ -0,0 +1,76 @@
+package outis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownReport(t *testing.T) {
+	var (
+		watch   = Watcher("watcher", "watcher", Logger(nopLogger{}))
+		started = make(chan struct{}, 2)
+		release = make(chan struct{})
+	)
+	defer close(release)
+
+	// A execução da rotina drained termina ao ser cancelada, a da rotina abandoned ignora o cancelamento
+	watch.Go(WithID("drained"), WithName("drained"), WithNotUseLoop(), WithScript(func(ctx Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return nil
+	}))
+	watch.Go(WithID("abandoned"), WithName("abandoned"), WithNotUseLoop(), WithScript(func(Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}))
+
+	for i := 0; i < cap(started); i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the executions did not start")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	report, err := watch.Shutdown(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+
+	require.Len(t, report.Drained, 1)
+	assert.Equal(t, "drained", report.Drained[0].ID)
+	require.Len(t, report.Abandoned, 1)
+	assert.Equal(t, "abandoned", report.Abandoned[0].ID)
+}
+
+func TestShutdownDrained(t *testing.T) {
+	var (
+		watch   = Watcher("watcher", "watcher", Logger(nopLogger{}))
+		started = make(chan struct{})
+	)
+
+	watch.Go(WithID("routine"), WithName("routine"), WithNotUseLoop(), WithScript(func(ctx Context) error {
+		close(started)
+		<-ctx.Done()
+		return nil
+	}))
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the execution did not start")
+	}
+
+	report, err := watch.Shutdown(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Drained, 1)
+	assert.Equal(t, "routine", report.Drained[0].ID)
+	assert.Empty(t, report.Abandoned)
+}
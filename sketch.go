@@ -0,0 +1,138 @@
+package outis
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// sketchRelativeAccuracy is the maximum relative error of the quantiles
+	sketchRelativeAccuracy = 0.01
+	// sketchMaxBins is the maximum number of bins kept by the sketch
+	sketchMaxBins = 2048
+	// sketchMinValue is the smallest absolute value distinguished from zero
+	sketchMinValue = 1e-9
+)
+
+// sketch is a quantile sketch with relative accuracy. The values are kept in bins with
+// logarithmic width, so the memory is bounded regardless of the number of values
+type sketch struct {
+	gamma    float64
+	logGamma float64
+	positive map[int]uint64
+	negative map[int]uint64
+	zero     uint64
+	count    uint64
+}
+
+func newSketch() *sketch {
+	gamma := (1 + sketchRelativeAccuracy) / (1 - sketchRelativeAccuracy)
+	return &sketch{
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+		positive: make(map[int]uint64),
+		negative: make(map[int]uint64),
+	}
+}
+
+func (s *sketch) index(value float64) int {
+	return int(math.Ceil(math.Log(value) / s.logGamma))
+}
+
+func (s *sketch) value(index int) float64 {
+	return 2 * math.Pow(s.gamma, float64(index)) / (s.gamma + 1)
+}
+
+// add records the value, NaN and infinite values are ignored
+func (s *sketch) add(value float64) {
+	switch {
+	case math.IsNaN(value), math.IsInf(value, 0):
+		return
+	case value > sketchMinValue:
+		s.positive[s.index(value)]++
+	case value < -sketchMinValue:
+		s.negative[s.index(-value)]++
+	default:
+		s.zero++
+	}
+
+	s.count++
+	s.collapse()
+}
+
+func (s *sketch) merge(other *sketch) {
+	for index, count := range other.positive {
+		s.positive[index] += count
+	}
+	for index, count := range other.negative {
+		s.negative[index] += count
+	}
+	s.zero += other.zero
+	s.count += other.count
+	s.collapse()
+}
+
+func (s *sketch) clone() *sketch {
+	clone := newSketch()
+	clone.merge(s)
+	return clone
+}
+
+// collapse merges the bins of the smallest absolute values while the sketch exceeds the maximum bins
+func (s *sketch) collapse() {
+	for len(s.positive)+len(s.negative) > sketchMaxBins {
+		bins := s.positive
+		if len(s.negative) > len(s.positive) {
+			bins = s.negative
+		}
+
+		indexes := sortedIndexes(bins)
+		bins[indexes[1]] += bins[indexes[0]]
+		delete(bins, indexes[0])
+	}
+}
+
+// quantile returns the approximated value of the quantile, q is clamped between 0 and 1.
+// It returns zero when the sketch is empty and NaN when q is NaN
+func (s *sketch) quantile(q float64) float64 {
+	switch {
+	case math.IsNaN(q):
+		return math.NaN()
+	case s.count == 0:
+		return 0
+	}
+
+	var (
+		rank       = uint64(math.Max(0, math.Min(1, q)) * float64(s.count-1))
+		cumulative uint64
+	)
+
+	negative := sortedIndexes(s.negative)
+	for i := len(negative) - 1; i >= 0; i-- {
+		if cumulative += s.negative[negative[i]]; cumulative > rank {
+			return -s.value(negative[i])
+		}
+	}
+
+	if cumulative += s.zero; cumulative > rank {
+		return 0
+	}
+
+	for _, index := range sortedIndexes(s.positive) {
+		if cumulative += s.positive[index]; cumulative > rank {
+			return s.value(index)
+		}
+	}
+
+	// O rank é sempre menor que a contagem, então um dos bins é retornado antes
+	return 0
+}
+
+func sortedIndexes(bins map[int]uint64) []int {
+	indexes := make([]int, 0, len(bins))
+	for index := range bins {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
@@ -0,0 +1,64 @@
+package outis
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SQLPlaceholder defines the style of the parameter placeholders used in the queries of the SQL store and locker
+type SQLPlaceholder int
+
+const (
+	// PlaceholderQuestion uses the '?' placeholder, supported by drivers like SQLite and MySQL
+	PlaceholderQuestion SQLPlaceholder = iota
+	// PlaceholderDollar uses the numbered '$1' placeholders, required by PostgreSQL
+	PlaceholderDollar
+)
+
+// SQLOption defines the option type of the SQL store and locker
+type SQLOption func(*sqlOptions)
+
+type sqlOptions struct {
+	placeholder SQLPlaceholder
+}
+
+// WithPlaceholder defines the style of the placeholders of the queries, by default PlaceholderQuestion is used
+func WithPlaceholder(placeholder SQLPlaceholder) SQLOption {
+	return func(opts *sqlOptions) { opts.placeholder = placeholder }
+}
+
+// newSQLOptions applies the options over the default ones
+func newSQLOptions(opts ...SQLOption) sqlOptions {
+	var options sqlOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// rebind rewrites the '?' placeholders of the query in the style of the options
+func (opts sqlOptions) rebind(query string) string {
+	if opts.placeholder != PlaceholderDollar {
+		return query
+	}
+
+	var (
+		builder strings.Builder
+		n       int
+	)
+
+	builder.Grow(len(query) + 8)
+	for i := 0; i < len(query); i++ {
+		if query[i] != '?' {
+			builder.WriteByte(query[i])
+			continue
+		}
+
+		n++
+		builder.WriteByte('$')
+		builder.WriteString(strconv.Itoa(n))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,98 @@
+package outis
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var sqlIdentifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
+// sqlLocker implements a Locker using a table of a SQL database
+type sqlLocker struct {
+	db    *sql.DB
+	table string
+	opts  sqlOptions
+}
+
+// NewSQLLocker creates a Locker that keeps the leases in a table of the database.
+// The queries use the '?' placeholder by default, supported by drivers like SQLite and MySQL,
+// WithPlaceholder(PlaceholderDollar) must be used with PostgreSQL.
+// The table can be created with CreateSQLLockerTable
+func NewSQLLocker(db *sql.DB, table string, opts ...SQLOption) (Locker, error) {
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name '%s'", table)
+	}
+
+	return &sqlLocker{db: db, table: table, opts: newSQLOptions(opts...)}, nil
+}
+
+// CreateSQLLockerTable creates the table used by the SQL locker if it does not exist
+func CreateSQLLockerTable(ctx context.Context, db *sql.DB, table string) error {
+	if !sqlIdentifier.MatchString(table) {
+		return fmt.Errorf("invalid table name '%s'", table)
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		lock_key VARCHAR(255) NOT NULL PRIMARY KEY,
+		owner VARCHAR(255) NOT NULL,
+		expires_at BIGINT NOT NULL
+	)`, table))
+
+	return err
+}
+
+// Lock tries to acquire the lock of the key for the owner during the ttl
+func (l *sqlLocker) Lock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	// Assume o lock expirado ou já pertencente ao owner
+	result, err := l.db.ExecContext(ctx,
+		l.opts.rebind(fmt.Sprintf("UPDATE %s SET owner = ?, expires_at = ? WHERE lock_key = ? AND (expires_at < ? OR owner = ?)", l.table)),
+		owner, now.Add(ttl).UnixNano(), key, now.UnixNano(), owner)
+	if err != nil {
+		return false, err
+	}
+
+	if rows, err := result.RowsAffected(); err != nil || rows > 0 {
+		return err == nil, err
+	}
+
+	_, err = l.db.ExecContext(ctx,
+		l.opts.rebind(fmt.Sprintf("INSERT INTO %s (lock_key, owner, expires_at) VALUES (?, ?, ?)", l.table)),
+		key, owner, now.Add(ttl).UnixNano())
+	if err == nil {
+		return true, nil
+	}
+
+	// A falha na inserção é esperada quando outro owner possui o lock
+	var count int
+	if errCount := l.db.QueryRowContext(ctx,
+		l.opts.rebind(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE lock_key = ?", l.table)), key).Scan(&count); errCount != nil || count == 0 {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// Refresh extends the lease of a lock held by the owner
+func (l *sqlLocker) Refresh(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	result, err := l.db.ExecContext(ctx,
+		l.opts.rebind(fmt.Sprintf("UPDATE %s SET expires_at = ? WHERE lock_key = ? AND owner = ?", l.table)),
+		time.Now().Add(ttl).UnixNano(), key, owner)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// Unlock releases the lock held by the owner
+func (l *sqlLocker) Unlock(ctx context.Context, key, owner string) error {
+	_, err := l.db.ExecContext(ctx,
+		l.opts.rebind(fmt.Sprintf("DELETE FROM %s WHERE lock_key = ? AND owner = ?", l.table)), key, owner)
+	return err
+}
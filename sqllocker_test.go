@@ -0,0 +1,45 @@
+package outis
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// newTestDB opens a SQLite database closed at the end of the test
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "outis.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLLocker(t *testing.T) {
+	for _, placeholder := range []SQLPlaceholder{PlaceholderQuestion, PlaceholderDollar} {
+		placeholder := placeholder
+		name := map[SQLPlaceholder]string{PlaceholderQuestion: "question", PlaceholderDollar: "dollar"}[placeholder]
+
+		// O SQLite aceita os dois estilos de placeholder
+		t.Run(name, func(t *testing.T) {
+			testLocker(t, func(t *testing.T) Locker {
+				db := newTestDB(t)
+				require.NoError(t, CreateSQLLockerTable(context.Background(), db, "outis_locks"))
+
+				locker, err := NewSQLLocker(db, "outis_locks", WithPlaceholder(placeholder))
+				require.NoError(t, err)
+				return locker
+			})
+		})
+	}
+}
+
+func TestSQLLockerInvalidTable(t *testing.T) {
+	_, err := NewSQLLocker(nil, "locks; DROP TABLE users")
+	assert.Error(t, err)
+	assert.Error(t, CreateSQLLockerTable(context.Background(), nil, "1locks"))
+}
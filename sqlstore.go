@@ -0,0 +1,198 @@
+package outis
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sqlStore implements an ExecutionStore using a table of a SQL database
+type sqlStore struct {
+	db    *sql.DB
+	table string
+	opts  sqlOptions
+}
+
+// NewSQLStore creates an ExecutionStore that keeps the executions in a table of the database.
+// The queries use the '?' placeholder by default, supported by drivers like SQLite and MySQL,
+// WithPlaceholder(PlaceholderDollar) must be used with PostgreSQL.
+// The table can be created with CreateSQLStoreTable
+func NewSQLStore(db *sql.DB, table string, opts ...SQLOption) (ExecutionStore, error) {
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name '%s'", table)
+	}
+
+	return &sqlStore{db: db, table: table, opts: newSQLOptions(opts...)}, nil
+}
+
+// CreateSQLStoreTable creates the table used by the SQL store and its index if they do not exist
+func CreateSQLStoreTable(ctx context.Context, db *sql.DB, table string) error {
+	if !sqlIdentifier.MatchString(table) {
+		return fmt.Errorf("invalid table name '%s'", table)
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		watcher_id VARCHAR(255) NOT NULL,
+		routine_id VARCHAR(255) NOT NULL,
+		routine_name VARCHAR(255) NOT NULL,
+		execution_id VARCHAR(255) NOT NULL,
+		attempt INTEGER NOT NULL,
+		scheduled_at BIGINT NOT NULL,
+		catch_up BOOLEAN NOT NULL,
+		triggered BOOLEAN NOT NULL,
+		started_at BIGINT NOT NULL,
+		finished_at BIGINT NOT NULL,
+		latency BIGINT NOT NULL,
+		outcome VARCHAR(32) NOT NULL,
+		error TEXT,
+		metadata TEXT,
+		indicators TEXT,
+		histograms TEXT
+	)`, table))
+	if err != nil {
+		return err
+	}
+
+	index := strings.ReplaceAll(table, ".", "_") + "_routine_started_at"
+	_, err = db.ExecContext(ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (routine_id, started_at)", index, table))
+
+	return err
+}
+
+// Save inserts the execution in the table
+func (s *sqlStore) Save(ctx context.Context, execution Execution) error {
+	metadata, err := json.Marshal(execution.Metadata)
+	if err != nil {
+		return err
+	}
+
+	indicators, err := json.Marshal(execution.Indicators)
+	if err != nil {
+		return err
+	}
+
+	histograms, err := json.Marshal(execution.Histograms)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		s.opts.rebind(fmt.Sprintf(`INSERT INTO %s (watcher_id, routine_id, routine_name, execution_id, attempt, scheduled_at, catch_up, triggered,
+			started_at, finished_at, latency, outcome, error, metadata, indicators, histograms) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, s.table)),
+		execution.WatcherID, execution.RoutineID, execution.RoutineName, execution.ID, execution.Attempt,
+		unixNano(execution.ScheduledAt), execution.CatchUp, execution.Triggered,
+		execution.StartedAt.UnixNano(), execution.FinishedAt.UnixNano(), int64(execution.Latency),
+		string(execution.Outcome), execution.Error, string(metadata), string(indicators), string(histograms))
+
+	return err
+}
+
+// Query returns the executions that match the query, the most recent first
+func (s *sqlStore) Query(ctx context.Context, query ExecutionQuery) ([]Execution, error) {
+	var (
+		conditions = []string{"1 = 1"}
+		args       []interface{}
+	)
+
+	if query.RoutineID != "" {
+		conditions, args = append(conditions, "routine_id = ?"), append(args, query.RoutineID.ToString())
+	}
+	if !query.From.IsZero() {
+		conditions, args = append(conditions, "started_at >= ?"), append(args, query.From.UnixNano())
+	}
+	if !query.To.IsZero() {
+		conditions, args = append(conditions, "started_at < ?"), append(args, query.To.UnixNano())
+	}
+	if query.Outcome != "" {
+		conditions, args = append(conditions, "outcome = ?"), append(args, string(query.Outcome))
+	}
+	if query.Scheduled {
+		conditions, args = append(conditions, "triggered = ? AND scheduled_at > 0"), append(args, false)
+	}
+
+	order := "started_at"
+	if query.SortByScheduledAt {
+		order = "scheduled_at"
+	}
+
+	statement := fmt.Sprintf(`SELECT watcher_id, routine_id, routine_name, execution_id, attempt, scheduled_at, catch_up, triggered,
+		started_at, finished_at, latency, outcome, error, metadata, indicators, histograms FROM %s WHERE %s ORDER BY %s DESC`,
+		s.table, strings.Join(conditions, " AND "), order)
+	if query.Limit > 0 {
+		statement += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.opts.rebind(statement), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	executions := make([]Execution, 0)
+	for rows.Next() {
+		var (
+			execution                          Execution
+			scheduledAt, startedAt, finishedAt int64
+			latency                            int64
+			outcome                            string
+			errMsg, metadata, indicators, hist sql.NullString
+		)
+
+		if err = rows.Scan(&execution.WatcherID, &execution.RoutineID, &execution.RoutineName, &execution.ID,
+			&execution.Attempt, &scheduledAt, &execution.CatchUp, &execution.Triggered, &startedAt, &finishedAt, &latency, &outcome,
+			&errMsg, &metadata, &indicators, &hist); err != nil {
+			return nil, err
+		}
+
+		execution.StartedAt, execution.FinishedAt = time.Unix(0, startedAt), time.Unix(0, finishedAt)
+		if scheduledAt > 0 {
+			execution.ScheduledAt = time.Unix(0, scheduledAt)
+		}
+		execution.Latency, execution.Outcome, execution.Error = time.Duration(latency), ExecutionOutcome(outcome), errMsg.String
+
+		if err = unmarshalColumn(metadata, &execution.Metadata); err != nil {
+			return nil, err
+		}
+		if err = unmarshalColumn(indicators, &execution.Indicators); err != nil {
+			return nil, err
+		}
+		if err = unmarshalColumn(hist, &execution.Histograms); err != nil {
+			return nil, err
+		}
+
+		executions = append(executions, execution)
+	}
+
+	return executions, rows.Err()
+}
+
+// Prune deletes the executions started before the time
+func (s *sqlStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, s.opts.rebind(fmt.Sprintf("DELETE FROM %s WHERE started_at < ?", s.table)), before.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+
+	removed, err := result.RowsAffected()
+	return int(removed), err
+}
+
+// unixNano returns the time in nanoseconds, the zero time is kept as zero
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// unmarshalColumn decodes a JSON column, ignoring null values
+func unmarshalColumn(column sql.NullString, value interface{}) error {
+	if !column.Valid || column.String == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(column.String), value)
+}
@@ -0,0 +1,42 @@
+package outis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLStore(t *testing.T) {
+	for _, placeholder := range []SQLPlaceholder{PlaceholderQuestion, PlaceholderDollar} {
+		placeholder := placeholder
+		name := map[SQLPlaceholder]string{PlaceholderQuestion: "question", PlaceholderDollar: "dollar"}[placeholder]
+
+		// O SQLite aceita os dois estilos de placeholder
+		t.Run(name, func(t *testing.T) {
+			testExecutionStore(t, func(t *testing.T) ExecutionStore {
+				db := newTestDB(t)
+				require.NoError(t, CreateSQLStoreTable(context.Background(), db, "outis_executions"))
+
+				store, err := NewSQLStore(db, "outis_executions", WithPlaceholder(placeholder))
+				require.NoError(t, err)
+				return store
+			})
+		})
+	}
+}
+
+func TestSQLStoreInvalidTable(t *testing.T) {
+	_, err := NewSQLStore(nil, "executions; DROP TABLE users")
+	assert.Error(t, err)
+	assert.Error(t, CreateSQLStoreTable(context.Background(), nil, "1executions"))
+}
+
+func TestSQLRebind(t *testing.T) {
+	query := "UPDATE locks SET owner = ? WHERE lock_key = ? AND expires_at < ?"
+
+	assert.Equal(t, query, newSQLOptions().rebind(query))
+	assert.Equal(t, "UPDATE locks SET owner = $1 WHERE lock_key = $2 AND expires_at < $3",
+		newSQLOptions(WithPlaceholder(PlaceholderDollar)).rebind(query))
+}
@@ -0,0 +1,191 @@
+package outis
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// historyPruneInterval is the interval between the prunings of the execution history
+	historyPruneInterval = time.Hour
+	// historySaveTimeout is the maximum duration of the recording of an execution in the store
+	historySaveTimeout = 30 * time.Second
+)
+
+// ExecutionOutcome defines the result of an execution
+type ExecutionOutcome string
+
+const (
+	// OutcomeSuccess is the outcome of an execution finished without error
+	OutcomeSuccess ExecutionOutcome = "success"
+	// OutcomeError is the outcome of an execution finished with error
+	OutcomeError ExecutionOutcome = "error"
+	// OutcomeTimeout is the outcome of an execution that exceeded the timeout
+	OutcomeTimeout ExecutionOutcome = "timeout"
+)
+
+// Execution defines the record of an execution attempt kept in the history
+type Execution struct {
+	WatcherID   string              `json:"watcher_id"`
+	RoutineID   string              `json:"routine_id"`
+	RoutineName string              `json:"routine_name"`
+	ID          string              `json:"id"`
+	Attempt     int                 `json:"attempt"`
+	ScheduledAt time.Time           `json:"scheduled_at"`
+	CatchUp     bool                `json:"catch_up,omitempty"`
+	Triggered   bool                `json:"triggered,omitempty"`
+	StartedAt   time.Time           `json:"started_at"`
+	FinishedAt  time.Time           `json:"finished_at"`
+	Latency     time.Duration       `json:"latency"`
+	Outcome     ExecutionOutcome    `json:"outcome"`
+	Error       string              `json:"error,omitempty"`
+	Metadata    Metadata            `json:"metadata,omitempty"`
+	Indicators  map[string]float64  `json:"indicators,omitempty"`
+	Histograms  []HistogramSnapshot `json:"histograms,omitempty"`
+}
+
+// ExecutionQuery defines the filters of a query to the history
+type ExecutionQuery struct {
+	// RoutineID filters the executions of the routine, when empty every routine is returned
+	RoutineID ID
+	// From filters the executions started at or after the time, when not zero
+	From time.Time
+	// To filters the executions started before the time, when not zero
+	To time.Time
+	// Outcome filters the executions with the outcome, when empty every outcome is returned
+	Outcome ExecutionOutcome
+	// Scheduled filters the executions of the schedule of the routine, including the catch-ups,
+	// excluding the triggered executions and the executions without scheduled time
+	Scheduled bool
+	// SortByScheduledAt returns the most recent scheduled time first, instead of the most recent start
+	SortByScheduledAt bool
+	// Limit is the maximum amount of executions returned, when zero every execution is returned
+	Limit int
+}
+
+// ExecutionStore defines where the history of the executions is kept
+type ExecutionStore interface {
+	// Save records an execution
+	Save(ctx context.Context, execution Execution) error
+	// Query returns the executions that match the query, the most recent first
+	// according to the start or, with SortByScheduledAt, to the scheduled time
+	Query(ctx context.Context, query ExecutionQuery) ([]Execution, error)
+	// Prune removes the executions started before the time, returning how many were removed
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// history defines the store of the executions of a watcher
+type history struct {
+	store     ExecutionStore
+	retention time.Duration
+	// pending são as execuções sendo gravadas, aguardadas ao encerrar o watcher
+	pending sync.WaitGroup
+}
+
+// newExecutionRecord creates the record of the execution from the metric event
+func newExecutionRecord(event EventMetric) Execution {
+	execution := Execution{
+		WatcherID:   event.Watcher.ID,
+		RoutineID:   event.Routine.ID,
+		RoutineName: event.Routine.Name,
+		ID:          event.ID,
+		Attempt:     event.Attempt,
+		ScheduledAt: event.ScheduledAt,
+		CatchUp:     event.CatchUp,
+		Triggered:   event.Triggered,
+		StartedAt:   event.StartedAt,
+		FinishedAt:  event.FinishedAt,
+		Latency:     event.Latency,
+		Outcome:     OutcomeSuccess,
+		Error:       event.Error,
+		Metadata:    event.Metadata,
+	}
+
+	switch {
+	case event.Timeout:
+		execution.Outcome = OutcomeTimeout
+	case event.Error != "":
+		execution.Outcome = OutcomeError
+	}
+
+	if len(event.Indicators) > 0 {
+		execution.Indicators = make(map[string]float64, len(event.Indicators))
+		for _, indicator := range event.Indicators {
+			execution.Indicators[indicator.GetKey()] = indicator.GetValue()
+		}
+	}
+
+	for _, histogram := range event.Histograms {
+		execution.Histograms = append(execution.Histograms, histogram.Snapshot())
+	}
+
+	return execution
+}
+
+// record saves the execution in the history of the watcher in the background, so that a slow
+// store does not delay the routine. The errors are only logged, so that a failure of the store
+// does not affect the execution of the routine
+func (ctx *ContextImpl) record(event EventMetric) {
+	h := ctx.Watcher.history
+	if h == nil {
+		return
+	}
+
+	execution, log := newExecutionRecord(event), ctx.log
+	h.pending.Add(1)
+	go func() {
+		defer h.pending.Done()
+
+		saveCtx, cancel := context.WithTimeout(context.Background(), historySaveTimeout)
+		defer cancel()
+
+		if err := h.store.Save(saveCtx, execution); err != nil {
+			log.Error(err, LogFields{"execution_id": event.ID})
+		}
+	}()
+}
+
+// flush waits for the executions being recorded until the context is done
+func (h *history) flush(ctx context.Context) error {
+	if h == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pruneHistory removes the executions older than the retention until the watcher is shut down
+func (watch *Watch) pruneHistory() {
+	defer watch.waiting()
+
+	for {
+		removed, err := watch.history.store.Prune(watch.context, watch.now().Add(-watch.history.retention))
+		if err != nil {
+			watch.log.Error(err)
+		} else if removed > 0 {
+			watch.log.Debug("Execution history pruned", LogFields{"removed": removed})
+		}
+
+		timer := watch.newTimer(historyPruneInterval)
+		watch.waiting()
+		select {
+		case <-watch.context.Done():
+			watch.running()
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+	}
+}
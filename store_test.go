@@ -0,0 +1,210 @@
+package outis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testExecutionStore verifies the behavior expected of every ExecutionStore implementation
+func testExecutionStore(t *testing.T, newStore func(t *testing.T) ExecutionStore) {
+	var (
+		ctx   = context.Background()
+		start = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	)
+
+	execution := func(routineID string, minute int, outcome ExecutionOutcome) Execution {
+		startedAt := start.Add(time.Duration(minute) * time.Minute)
+		return Execution{
+			WatcherID:   "watcher",
+			RoutineID:   routineID,
+			RoutineName: "routine " + routineID,
+			ID:          routineID + "-" + startedAt.Format("1504"),
+			Attempt:     1,
+			ScheduledAt: startedAt,
+			StartedAt:   startedAt,
+			FinishedAt:  startedAt.Add(time.Second),
+			Latency:     time.Second,
+			Outcome:     outcome,
+		}
+	}
+
+	ids := func(executions []Execution) []string {
+		result := make([]string, 0, len(executions))
+		for _, execution := range executions {
+			result = append(result, execution.ID)
+		}
+		return result
+	}
+
+	populate := func(t *testing.T, store ExecutionStore) {
+		for _, e := range []Execution{
+			execution("a", 0, OutcomeSuccess),
+			execution("b", 1, OutcomeSuccess),
+			execution("a", 2, OutcomeError),
+			execution("a", 3, OutcomeSuccess),
+			execution("b", 4, OutcomeTimeout),
+		} {
+			require.NoError(t, store.Save(ctx, e))
+		}
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		store := newStore(t)
+
+		saved := execution("a", 0, OutcomeError)
+		saved.Error, saved.CatchUp, saved.Triggered = "failure", true, true
+		saved.Metadata = Metadata{"processed": float64(10)}
+		saved.Indicators = map[string]float64{"rows": 42}
+		saved.Histograms = []HistogramSnapshot{{Key: "latency", Count: 1, Sum: 0.5}}
+		require.NoError(t, store.Save(ctx, saved))
+
+		executions, err := store.Query(ctx, ExecutionQuery{})
+		require.NoError(t, err)
+		require.Len(t, executions, 1)
+
+		got := executions[0]
+		assert.Equal(t, saved.ID, got.ID)
+		assert.Equal(t, saved.RoutineName, got.RoutineName)
+		assert.True(t, saved.StartedAt.Equal(got.StartedAt))
+		assert.True(t, saved.ScheduledAt.Equal(got.ScheduledAt))
+		assert.Equal(t, saved.Latency, got.Latency)
+		assert.Equal(t, saved.Outcome, got.Outcome)
+		assert.Equal(t, saved.Error, got.Error)
+		assert.True(t, got.CatchUp)
+		assert.True(t, got.Triggered)
+		assert.Equal(t, saved.Metadata, got.Metadata)
+		assert.Equal(t, saved.Indicators, got.Indicators)
+		require.Len(t, got.Histograms, 1)
+		assert.Equal(t, "latency", got.Histograms[0].Key)
+	})
+
+	t.Run("filters, order and limit", func(t *testing.T) {
+		store := newStore(t)
+		populate(t, store)
+
+		for _, tt := range []struct {
+			name  string
+			query ExecutionQuery
+			want  []string
+		}{
+			{"every execution, the most recent first", ExecutionQuery{}, []string{"b-0804", "a-0803", "a-0802", "b-0801", "a-0800"}},
+			{"routine", ExecutionQuery{RoutineID: "a"}, []string{"a-0803", "a-0802", "a-0800"}},
+			{"outcome", ExecutionQuery{RoutineID: "a", Outcome: OutcomeSuccess}, []string{"a-0803", "a-0800"}},
+			{"period", ExecutionQuery{From: start.Add(time.Minute), To: start.Add(3 * time.Minute)}, []string{"a-0802", "b-0801"}},
+			{"limit", ExecutionQuery{Limit: 2}, []string{"b-0804", "a-0803"}},
+			{"no match", ExecutionQuery{RoutineID: "c"}, []string{}},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				executions, err := store.Query(ctx, tt.query)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, ids(executions))
+			})
+		}
+	})
+
+	t.Run("scheduled executions", func(t *testing.T) {
+		store := newStore(t)
+		populate(t, store)
+
+		// A execução manual é a mais recente, e a execução perdida foi iniciada depois do seu horário agendado
+		triggered := execution("a", 6, OutcomeSuccess)
+		triggered.Triggered = true
+		catchUp := execution("a", 5, OutcomeSuccess)
+		catchUp.ScheduledAt, catchUp.CatchUp = start.Add(-time.Minute), true
+		unscheduled := execution("a", 7, OutcomeSuccess)
+		unscheduled.ScheduledAt = time.Time{}
+		for _, e := range []Execution{triggered, catchUp, unscheduled} {
+			require.NoError(t, store.Save(ctx, e))
+		}
+
+		executions, err := store.Query(ctx, ExecutionQuery{RoutineID: "a", Scheduled: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a-0805", "a-0803", "a-0802", "a-0800"}, ids(executions))
+
+		executions, err = store.Query(ctx, ExecutionQuery{RoutineID: "a", Scheduled: true, SortByScheduledAt: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a-0803", "a-0802", "a-0800", "a-0805"}, ids(executions))
+
+		executions, err = store.Query(ctx, ExecutionQuery{RoutineID: "a", Outcome: OutcomeSuccess, Scheduled: true, SortByScheduledAt: true, Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a-0803"}, ids(executions))
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		store := newStore(t)
+		populate(t, store)
+
+		removed, err := store.Prune(ctx, start.Add(2*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 2, removed)
+
+		removed, err = store.Prune(ctx, start.Add(2*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 0, removed)
+
+		executions, err := store.Query(ctx, ExecutionQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b-0804", "a-0803", "a-0802"}, ids(executions))
+
+		require.NoError(t, store.Save(ctx, execution("a", 5, OutcomeSuccess)))
+		executions, err = store.Query(ctx, ExecutionQuery{RoutineID: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a-0805", "a-0803", "a-0802"}, ids(executions))
+	})
+}
+
+// blockingStore is an ExecutionStore whose saves wait until it is released
+type blockingStore struct {
+	ExecutionStore
+	release chan struct{}
+}
+
+func (s *blockingStore) Save(ctx context.Context, execution Execution) error {
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return s.ExecutionStore.Save(ctx, execution)
+}
+
+func TestHistorySavedInBackground(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir() + "/executions.jsonl")
+	require.NoError(t, err)
+
+	var (
+		store = &blockingStore{ExecutionStore: fileStore, release: make(chan struct{})}
+		impl  = newExitRecorder()
+		watch = newTestWatcher(t, Impl(impl), History(store, 0))
+	)
+
+	watch.Go(WithID("routine"), WithName("routine"), WithNotUseLoop(), WithScript(func(Context) error { return nil }))
+
+	// A rotina termina enquanto a gravação do histórico está bloqueada
+	assert.NoError(t, impl.wait(t).err)
+
+	close(store.release)
+	_, err = watch.Shutdown(context.Background())
+	require.NoError(t, err)
+
+	executions, err := fileStore.Query(context.Background(), ExecutionQuery{RoutineID: "routine"})
+	require.NoError(t, err)
+	assert.Len(t, executions, 1, "the shutdown must wait for the pending saves")
+}
+
+func TestHistoryFlushDeadline(t *testing.T) {
+	h := &history{}
+	h.pending.Add(1)
+	defer h.pending.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, h.flush(ctx), context.DeadlineExceeded)
+	assert.NoError(t, (*history)(nil).flush(context.Background()))
+}
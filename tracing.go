@@ -0,0 +1,61 @@
+package outis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the instrumentation library used in the spans
+const tracerName = "github.com/Brisanet/outis"
+
+// startSpan starts the span of an execution attempt, the returned context carries the
+// span so that the operations made by the script with it become child spans
+func (ctx *ContextImpl) startSpan() (context.Context, trace.Span) {
+	provider := ctx.Watcher.tracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return provider.Tracer(tracerName).Start(ctx.context, ctx.name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String("outis.watcher.id", ctx.Watcher.Id.ToString()),
+			attribute.String("outis.watcher.name", ctx.Watcher.Name),
+			attribute.String("outis.routine.id", ctx.routineID.ToString()),
+			attribute.String("outis.routine.path", ctx.Path),
+			attribute.String("outis.execution.id", ctx.id.ToString()),
+			attribute.Int("outis.execution.attempt", ctx.attempt),
+		),
+	)
+}
+
+// traceMetadata records the metadata of the execution in the span
+func (ctx *ContextImpl) traceMetadata(span trace.Span) {
+	metadata, _, _ := ctx.measures.snapshot(ctx.metadata)
+	for key, value := range metadata {
+		span.SetAttributes(attribute.String("outis.metadata."+key, fmt.Sprintf("%v", value)))
+	}
+}
+
+// endSpan records the error of the execution and ends the span,
+// the panics are recorded with the stack trace of the goroutine that panicked
+func endSpan(span trace.Span, err error) {
+	var panicErr *PanicError
+
+	switch {
+	case errors.As(err, &panicErr):
+		span.RecordError(err, trace.WithAttributes(attribute.String("exception.stacktrace", string(panicErr.Stack))))
+		span.SetStatus(codes.Error, "panic")
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
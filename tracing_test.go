@@ -0,0 +1,108 @@
+package outis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Brisanet/outis"
+	"github.com/Brisanet/outis/outistest"
+)
+
+// executeTraced executes the script once with a watcher that keeps the spans in memory
+func executeTraced(t *testing.T, script func(outis.Context) error) (tracetest.SpanStubs, error) {
+	t.Helper()
+
+	var (
+		provider, exporter = outistest.NewTracerProvider()
+		watch              = outis.Watcher("watcher", "watcher",
+			outis.Logger(outistest.NewLogRecorder()), outis.Impl(newMinimalOutis()), outis.TracerProvider(provider))
+	)
+	t.Cleanup(func() { watch.Shutdown(context.Background()) }) //nolint:errcheck
+
+	ctx, err := watch.NewContext(
+		outis.WithID("routine"),
+		outis.WithName("routine"),
+		outis.WithScript(script),
+	)
+	require.NoError(t, err)
+
+	err = ctx.Execute()
+	return exporter.GetSpans(), err
+}
+
+// spanAttributes returns the attributes of the span by key
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attributes := make(map[attribute.Key]attribute.Value, len(span.Attributes))
+	for _, kv := range span.Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestTracingSuccess(t *testing.T) {
+	spans, err := executeTraced(t, func(ctx outis.Context) error {
+		ctx.AddMetadata(outis.Metadata{"client": 42})
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	assert.Equal(t, "routine", span.Name)
+	assert.Equal(t, trace.SpanKindInternal, span.SpanKind)
+	assert.Equal(t, codes.Unset, span.Status.Code)
+	assert.Empty(t, span.Events)
+
+	attributes := spanAttributes(span)
+	assert.Equal(t, "watcher", attributes["outis.watcher.id"].AsString())
+	assert.Equal(t, "watcher", attributes["outis.watcher.name"].AsString())
+	assert.Equal(t, "routine", attributes["outis.routine.id"].AsString())
+	assert.NotEmpty(t, attributes["outis.routine.path"].AsString())
+	assert.NotEmpty(t, attributes["outis.execution.id"].AsString())
+	assert.EqualValues(t, 1, attributes["outis.execution.attempt"].AsInt64())
+	assert.Equal(t, "42", attributes["outis.metadata.client"].AsString())
+}
+
+func TestTracingFailure(t *testing.T) {
+	failure := errors.New("failure")
+
+	spans, err := executeTraced(t, func(outis.Context) error { return failure })
+	require.ErrorIs(t, err, failure)
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	assert.Equal(t, "routine", span.Name)
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.Equal(t, "failure", span.Status.Description)
+
+	// O erro é registrado como um evento de exceção do span
+	require.Len(t, span.Events, 1)
+	assert.Equal(t, "exception", span.Events[0].Name)
+}
+
+func TestTracingPanic(t *testing.T) {
+	spans, err := executeTraced(t, func(outis.Context) error { panic("boom") })
+
+	var panicErr *outis.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.Equal(t, "panic", span.Status.Description)
+
+	require.Len(t, span.Events, 1)
+	stacktrace := false
+	for _, kv := range span.Events[0].Attributes {
+		stacktrace = stacktrace || kv.Key == "exception.stacktrace" && kv.Value.AsString() != ""
+	}
+	assert.True(t, stacktrace, "the span must record the stack trace of the panic")
+}
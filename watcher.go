@@ -2,6 +2,7 @@ package outis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -12,6 +13,8 @@ import (
 	"strconv"
 	"syscall"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ID defines the type of identifier
@@ -28,22 +31,39 @@ type Watch struct {
 	Name  string    `json:"name"`
 	RunAt time.Time `json:"run_at"`
 
-	outis IOutis
-	log   ILogger
+	outis            IOutis
+	log              ILogger
+	location         *time.Location
+	registry         *registry
+	signals          *shutdownSignals
+	lock             *lockOptions
+	leadership       *leadership
+	tracerProvider   trace.TracerProvider
+	adminAddr        string
+	adminMiddlewares []AdminMiddleware
+	history          *history
+	clock            IClock
+	owner            string
+
+	context context.Context //nolint:containedctx
+	cancel  context.CancelFunc
 }
 
 // Watcher initializes a new watcher
 func Watcher(id, name string, opts ...WatcherOption) *Watch {
 	watch := &Watch{
-		Id:    ID(id),
-		Name:  name,
-		outis: newOutis(),
-		RunAt: time.Now(),
+		Id:       ID(id),
+		Name:     name,
+		outis:    NewOutis(),
+		registry: newRegistry(),
+		owner:    newOwner(),
 	}
+	watch.context, watch.cancel = context.WithCancel(context.Background())
 
 	for _, opt := range opts {
 		opt(watch)
 	}
+	watch.RunAt = watch.now()
 	if watch.log == nil {
 		logger, err := NewLogger(name)
 		if err != nil {
@@ -51,15 +71,64 @@ func Watcher(id, name string, opts ...WatcherOption) *Watch {
 		}
 		watch.log = logger
 	}
+	if watch.signals != nil {
+		go watch.handleSignals(watch.signals.timeout, watch.signals.signals)
+	}
+	if watch.leadership != nil {
+		watch.running()
+		go watch.elect()
+	}
+	if watch.history != nil && watch.history.retention > 0 {
+		watch.running()
+		go watch.pruneHistory()
+	}
+	if watch.adminAddr != "" {
+		go watch.listenAdmin(watch.adminAddr)
+	}
 
 	return watch
 }
 
-// Wait method responsible for keeping routines running
+// newContext creates a context of the watcher, used in the events not related to a routine
+func (watch *Watch) newContext() *ContextImpl {
+	return &ContextImpl{
+		id:                newID(),
+		name:              watch.Name,
+		metadata:          make(Metadata),
+		measures:          newMeasures(),
+		log:               watch.log,
+		Location:          watch.location,
+		RunAt:             watch.RunAt,
+		Watcher:           *watch,
+		context:           watch.context,
+		contextCancelFunc: func() {},
+	}
+}
+
+// metric returns the watcher data used in metrics and events
+func (watch *Watch) metric() WatcherMetric {
+	return WatcherMetric{
+		ID:    watch.Id.ToString(),
+		Name:  watch.Name,
+		RunAt: watch.RunAt,
+	}
+}
+
+// Wait method responsible for keeping routines running,
+// it returns when every routine finishes or when the watcher is shut down
 func (watch *Watch) Wait() {
-	if err := watch.outis.Wait(); err != nil {
-		watch.log.Error(err)
-		return
+	wait := make(chan error, 1)
+	go func() { wait <- watch.outis.Wait() }()
+
+	select {
+	case err := <-wait:
+		if err != nil {
+			watch.log.Error(err)
+		}
+
+		// Os saves do histórico têm o próprio timeout, por isso a espera é limitada
+		_ = watch.history.flush(context.Background())
+	case <-watch.registry.stopped:
 	}
 }
 
@@ -73,116 +142,376 @@ func Wait() {
 	}
 }
 
-// Go create a new routine in the watcher
+// Go create a new routine in the watcher. The routine fails with ErrDuplicateRoutine
+// when another routine with the same identifier is running in the watcher
 func (watch *Watch) Go(opts ...Option) {
-	watch.outis.Go(func() error {
+	watch.running()
+	watch.outis.Go(func() (err error) {
+		defer watch.waiting()
+
 		var (
-			childContext, childContextCancelFunc = context.WithCancel(context.Background())
-			ctx                                  = &ContextImpl{
-				id:                ID(strconv.FormatInt(rand.Int63(), 10)),
-				indicator:         make([]*Indicator, 0),
-				metadata:          make(Metadata),
-				log:               watch.log,
-				Interval:          time.Minute,
-				RunAt:             time.Now(),
-				Watcher:           *watch,
-				context:           childContext,
-				contextCancelFunc: childContextCancelFunc,
-			}
-			err error
+			ctx = watch.newRoutineContext(opts...)
+			r   = newRoutine(ctx)
 		)
 
-		for _, opt := range opts {
-			opt(ctx)
-		}
-
+		// A rotina é validada antes de registrada, pois a validação altera os campos lidos pelos snapshots
 		if err = ctx.validate(); err != nil {
+			r.exit(err)
 			return err
 		}
 
-		info := runtime.FuncForPC(reflect.ValueOf(ctx.script).Pointer())
-		file, line := info.FileLine(info.Entry())
-		ctx.Path = fmt.Sprintf("%s:%v", file, line)
+		if err = watch.registry.add(r); err != nil {
+			if errors.Is(err, errWatcherClosed) {
+				ctx.contextCancelFunc()
+				return nil
+			}
+			r.exit(err)
+			return err
+		}
+		// O término da rotina é notificado após a finalização das execuções em andamento
+		defer func() { r.exit(err) }()
+		defer r.executions.Wait()
 
-		if err := watch.outis.Init(ctx); err != nil {
+		if err = watch.outis.Init(ctx); err != nil {
 			return err
 		}
 
-		defer func() {
-			if r := recover(); r != nil {
-				ctx.log.Panic(fmt.Sprintf("%v", r))
+		return r.supervise(func(startup bool) error { return watch.loop(ctx, r, startup) })
+	})
+}
+
+// loop schedules the executions of the routine until its context is done, or executes
+// it a single time when the routine does not use a loop. The startup steps, the search of the
+// last execution in the history, the catch-up and the first execution before the interval, only
+// run in the first loop, the loops rebuilt by the restart policy resume the schedule
+func (watch *Watch) loop(ctx *ContextImpl, r *routine, startup bool) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = r.recoverLoop(rec)
+		}
+	}()
+
+	// O horário agendado da última execução do agendamento, mantido no histórico, é o horário anterior
+	// da primeira execução, e o da última execução com sucesso é o início das execuções perdidas
+	var last time.Time
+	if startup && watch.history != nil {
+		if r.previous, err = ctx.lastScheduledAt(""); err != nil {
+			ctx.LogError(err)
+		}
+		if ctx.catchUpPolicy != nil {
+			if last, err = ctx.lastScheduledAt(OutcomeSuccess); err != nil {
+				ctx.LogError(err)
 			}
-		}()
+		}
+	}
 
-		// TODO: refactor the execution logic below when add test
+	if ctx.notUseLoop {
+		r.setState(RoutineWaiting)
+		watch.awaitLeadership(ctx.context)
+		ctx.sleep(watch.now())
+		if err = ctx.context.Err(); err != nil {
+			return r.exitErr(err)
+		}
+
+		exec := ctx.newExecution()
+		defer exec.contextCancelFunc()
+		exec.scheduledAt = watch.now()
+		exec.previousScheduledAt = r.advance(tick{scheduledAt: exec.scheduledAt})
+		return r.exitErr(r.execute(exec))
+	}
+
+	// Executa as execuções perdidas enquanto o watcher estava parado
+	if startup && ctx.catchUpPolicy != nil && watch.awaitLeadership(ctx.context) {
+		r.catchUp(last)
+	}
+
+	if startup && ctx.executeFirstTimeBeforeInterval && watch.awaitLeadership(ctx.context) {
+		ctx.sleep(watch.now())
+		now := watch.now()
+		if ctx.wait(ctx.perturbation(now)) {
+			r.dispatch(tick{scheduledAt: now})
+		}
+	}
 
-		if ctx.notUseLoop {
-			ctx.sleep(time.Now())
-			return ctx.execute()
+	var (
+		scheduled time.Time
+		// shift é o atraso da execução anterior em relação ao horário agendado, causado pelo
+		// offset e pelo jitter, desconsiderado no próximo agendamento para não acumular
+		shift time.Duration
+		// pending é o horário agendado interrompido por uma execução manual, que continua valendo
+		pending      time.Time
+		pendingShift time.Duration
+	)
+	r.setState(RoutineWaiting)
+	for {
+		// Com eleição de líder, a rotina fica pausada enquanto o watcher não for o líder
+		if !watch.awaitLeadership(ctx.context) {
+			return r.exitErr(ctx.context.Err())
 		}
 
-		if ctx.executeFirstTimeBeforeInterval {
-			ctx.sleep(time.Now())
-			if err = ctx.execute(); err != nil {
-				ctx.log.Error(err)
+		ctx.sleep(watch.now())
+
+		// Com exceção da política delay, o agendamento segue o horário previsto
+		// da execução anterior, independente da duração das execuções
+		now := watch.now()
+		next := ctx.next(now.Add(-shift))
+		if ctx.overlap.mode != overlapDelay && !scheduled.IsZero() {
+			if fromScheduled := ctx.next(scheduled); !fromScheduled.Before(now) {
+				next = fromScheduled
 			}
 		}
 
-		ticker := time.NewTicker(ctx.Interval)
-		for {
-			ctx.sleep(time.Now())
-
-			ctx.log.Info("Starting ticker " + ctx.Interval.String())
-			ticker.Reset(ctx.Interval)
-			select {
-			// Espera o contexto ser finalizado
-			case <-ctx.context.Done():
-				return ctx.context.Err()
-			// Espera a próxima execução com base no ticker
-			case _, isOpen := <-ticker.C:
-				ticker.Stop()
-				if !isOpen {
-					return nil
-				}
-
-				var err error
-
-				// Caso o contexto esteja com erro o script é finalizado
-				if err = ctx.context.Err(); err != nil {
-					return err
-				}
-
-				if err = ctx.execute(); err != nil {
-					ctx.log.Error(err)
-					continue
-				}
+		shift = ctx.perturbation(next)
+		if !pending.IsZero() && !pending.Add(pendingShift).Before(now) {
+			next, shift = pending, pendingShift
+		}
+		pending = time.Time{}
+		runAt := next.Add(shift)
+
+		ctx.log.Info("Next execution at " + runAt.Format("02/01/2006 15:04:05"))
+		r.setNextRun(runAt)
+		timer := watch.newTimer(runAt.Sub(now))
+		watch.waiting()
+		select {
+		// Espera o contexto ser finalizado
+		case <-ctx.context.Done():
+			watch.running()
+			timer.Stop()
+			return r.exitErr(ctx.context.Err())
+		// Execução solicitada fora do agendamento, mesmo com a rotina pausada
+		case t := <-r.trigger:
+			watch.running()
+			timer.Stop()
+			// A execução manual não altera o próximo horário agendado
+			pending, pendingShift = next, shift
+			if !watch.IsLeader() {
+				ctx.LogWarn("Trigger ignored, the watcher is not the leader")
+				continue
+			}
+
+			t.scheduledAt = watch.now()
+			r.dispatch(t)
+		// Espera a próxima execução com base no agendamento
+		case <-timer.C():
+			// Caso o contexto esteja com erro o script é finalizado
+			if err = ctx.context.Err(); err != nil {
+				return r.exitErr(err)
+			}
+
+			if !watch.IsLeader() {
+				continue
 			}
+
+			scheduled = next
+			if !ctx.dayAllowed(next) {
+				ctx.LogDebug("Execution skipped, the day is not allowed for the routine")
+				continue
+			}
+
+			if r.isPaused() {
+				ctx.LogDebug("Execution skipped, the routine is paused")
+				continue
+			}
+
+			r.dispatch(tick{scheduledAt: next})
 		}
-	})
+	}
+}
+
+// newRoutineContext creates the context of a routine with the options
+func (watch *Watch) newRoutineContext(opts ...Option) *ContextImpl {
+	childContext, childContextCancelFunc := context.WithCancel(watch.context)
+	ctx := &ContextImpl{
+		id:                newID(),
+		measures:          newMeasures(),
+		metadata:          make(Metadata),
+		log:               watch.log,
+		Interval:          time.Minute,
+		Location:          watch.location,
+		RunAt:             watch.now(),
+		Watcher:           *watch,
+		context:           childContext,
+		contextCancelFunc: childContextCancelFunc,
+	}
+
+	for _, opt := range opts {
+		opt(ctx)
+	}
+
+	if ctx.script != nil {
+		info := runtime.FuncForPC(reflect.ValueOf(ctx.script).Pointer())
+		file, line := info.FileLine(info.Entry())
+		ctx.Path = fmt.Sprintf("%s:%v", file, line)
+	}
+
+	return ctx
+}
+
+// NewContext creates the context of a single execution of a routine with the options, without scheduling
+// the routine. The context can be passed directly to a script or executed with Execute, such as in the tests
+// of the scripts, see the outistest package
+func (watch *Watch) NewContext(opts ...Option) (*ContextImpl, error) {
+	ctx := watch.newRoutineContext(opts...)
+	if err := ctx.validate(); err != nil {
+		ctx.contextCancelFunc()
+		return nil, err
+	}
+
+	exec := ctx.newExecution()
+	exec.scheduledAt = watch.now()
+	return exec, nil
+}
+
+// Execute executes the script of the context a single time, as a scheduled execution: with the hooks
+// of the main interface, the retry policy, the timeout and the events. The error is also sent to OnError
+func (ctx *ContextImpl) Execute() error {
+	ctx.Watcher.running()
+	defer ctx.Watcher.waiting()
+
+	err := ctx.execute()
+	if err != nil {
+		ctx.Watcher.onError(ctx, err)
+	}
+
+	return err
 }
 
+// newID generates a new execution identifier
+func newID() ID {
+	return ID(strconv.FormatInt(rand.Int63(), 10))
+}
+
+// execute executes the script, retrying the execution according to the retry policy.
+// When a distributed lock is defined, the execution is skipped if the lock is held by another
+// process, or if the catch-up of the routine was claimed by another process
 func (ctx *ContextImpl) execute() error {
-	initialTime := time.Now()
+	if ctx.Watcher.lock != nil {
+		release, acquired, err := ctx.lock()
+		if err != nil {
+			return err
+		}
+
+		if !acquired {
+			ctx.LogDebug("Execution skipped, the lock is held by another process")
+			return nil
+		}
+		defer release()
+
+		if ctx.catchUp {
+			claimed, err := ctx.claimCatchUp()
+			if err != nil {
+				return err
+			}
+
+			if !claimed {
+				ctx.LogDebug("Catch-up skipped, the catch-up was claimed by another process", LogFields{"scheduled_at": ctx.scheduledAt.String()})
+				return nil
+			}
+		}
+	}
+
+	var (
+		startedAt = ctx.Watcher.now()
+		delay     time.Duration
+		retrying  bool
+	)
+
+	for ctx.attempt = 1; ; ctx.attempt++ {
+		ctx.id = newID()
+
+		err := ctx.executeAttempt()
+		if err == nil || ctx.retryPolicy == nil {
+			return err
+		}
+
+		delay, retrying = ctx.retryPolicy.next(ctx.attempt, ctx.Watcher.since(startedAt), delay, err)
+		retrying = retrying && ctx.context.Err() == nil
+
+		ctx.Watcher.outis.Event(ctx, EventRetry{
+			ID:       ctx.id.ToString(),
+			Attempt:  ctx.attempt,
+			Delay:    delay,
+			Err:      err,
+			Retrying: retrying,
+			Watcher:  ctx.Watcher.metric(),
+			Routine:  ctx.routineMetric(),
+		})
+
+		if !retrying || !ctx.wait(delay) {
+			return err
+		}
+	}
+}
+
+// executeAttempt executes the script a single time
+func (ctx *ContextImpl) executeAttempt() (err error) {
+	initialTime := ctx.Watcher.now()
+
+	spanCtx, span := ctx.startSpan()
+	defer func() { endSpan(span, err) }()
 	defer func() {
+		// O panic é reportado como erro da tentativa, sem interromper a rotina
 		if r := recover(); r != nil {
-			ctx.log.Panic(fmt.Sprintf("%v", r))
+			err = newPanicError(ctx.routineID, ctx.id, r)
+			ctx.latency = ctx.Watcher.since(initialTime)
+			ctx.metrics(&ctx.Watcher, initialTime, err)
 		}
 	}()
 
-	if err := ctx.Watcher.outis.Before(ctx); err != nil {
+	if err = ctx.Watcher.outis.Before(ctx); err != nil {
 		return err
 	}
 
-	if err := ctx.script(ctx.Copy()); err != nil {
+	err = ctx.run(spanCtx)
+	ctx.latency = ctx.Watcher.since(initialTime)
+	ctx.traceMetadata(span)
+	if err != nil {
+		ctx.metrics(&ctx.Watcher, initialTime, err)
 		return err
 	}
 
-	ctx.latency = time.Since(initialTime)
-	if err := ctx.Watcher.outis.After(ctx); err != nil {
+	if err = ctx.Watcher.outis.After(ctx); err != nil {
 		return err
 	}
 
-	ctx.metrics(&ctx.Watcher, initialTime)
+	ctx.metrics(&ctx.Watcher, initialTime, nil)
 
 	return nil
 }
+
+// run executes the script with a copy of the context based on the given context. When a timeout
+// is defined the script receives a context with deadline and the execution is abandoned if the
+// deadline is exceeded
+func (ctx *ContextImpl) run(baseCtx context.Context) error {
+	if ctx.timeout <= 0 {
+		return ctx.script(ctx.Copy(baseCtx))
+	}
+
+	timeoutCtx, cancel := withClockTimeout(baseCtx, &ctx.Watcher, ctx.timeout)
+	defer cancel()
+
+	// A cópia é criada antes da goroutine, pois a execução abandonada pelo timeout continua alterando o contexto
+	scriptCtx := ctx.Copy(timeoutCtx)
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- newPanicError(ctx.routineID, ctx.id, r)
+			}
+		}()
+
+		done <- ctx.script(scriptCtx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timeoutCtx.Done():
+		// O cancelamento da rotina aguarda a finalização do script
+		if !errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			return <-done
+		}
+
+		return &TimeoutError{RoutineID: ctx.routineID, ID: ctx.id, Timeout: ctx.timeout}
+	}
+}